@@ -1,37 +1,156 @@
 package main
 
 import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+	_ "time/tzdata" // database zona waktu ikut di-embed, jadi Asia/Jakarta tetap ada di image minimal
+
 	"github.com/IkrmMrbsy/mrt-schedules/internal/api/handler"
 	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
 	stationUsecase "github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/calendar"
 	"github.com/IkrmMrbsy/mrt-schedules/internal/config"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/farerule"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/client"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/middleware"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/snapshot"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	cfg := config.LoadConfig()
 
-	stationService := station.NewService(cfg.HttpTimeout, cfg.MRTApiURL)
-	stationUsecase := stationUsecase.NewUsecase(stationService)
+	// HTTP client upstream dengan retry + backoff untuk error sementara,
+	// dan circuit breaker supaya request tidak menumpuk saat upstream down
+	upstream := client.NewClient(&http.Client{Timeout: cfg.HttpTimeout}, client.RetryPolicy{
+		MaxAttempts:     cfg.RetryMaxAttempts,
+		BaseDelay:       cfg.RetryBaseDelay,
+		MaxDelay:        cfg.RetryMaxDelay,
+		Jitter:          cfg.RetryJitter,
+		RetryableStatus: cfg.RetryStatuses,
+	}, client.WithBreaker(client.BreakerPolicy{
+		FailureThreshold: cfg.BreakerFailureThreshold,
+		OpenTimeout:      cfg.BreakerOpenTimeout,
+	}))
+
+	// Snapshot disk dipakai sebagai cadangan saat upstream tidak bisa dihubungi
+	var serviceOpts []station.Option
+	store, err := snapshot.NewStore(cfg.SnapshotDir)
+	if err != nil {
+		if cfg.OfflineMode {
+			log.Fatalf("offline mode requires snapshot dir %s: %v", cfg.SnapshotDir, err)
+		}
+		log.Printf("Snapshot store disabled: %v", err)
+	} else {
+		serviceOpts = append(serviceOpts, station.WithSnapshotStore(store))
+	}
+	serviceOpts = append(serviceOpts,
+		station.WithOfflineMode(cfg.OfflineMode),
+		station.WithClient(upstream),
+		station.WithResourceURL(station.ResourceStations, cfg.MRTStationsURL),
+		station.WithResourceURL(station.ResourceSchedules, cfg.MRTSchedulesURL),
+		station.WithResourceURL(station.ResourceFares, cfg.MRTFaresURL),
+		station.WithReuseWindow(cfg.UpstreamReuseWindow),
+	)
+
+	// Service asli dibungkus cache supaya API MRT tidak dipanggil di setiap request
+	stationService := station.NewCachedService(
+		station.NewService(cfg.HttpTimeout, cfg.MRTApiURL, serviceOpts...),
+		station.CacheTTL{
+			Stations:    cfg.CacheTTLStations,
+			Schedules:   cfg.CacheTTLSchedules,
+			Fares:       cfg.CacheTTLFares,
+			StaleWindow: cfg.CacheStaleWindow,
+		},
+	)
+
+	// Kalender libur nasional untuk memilih jadwal Biasa/Libur
+	holidays := calendar.New(cfg.HolidayFile)
+	if err := holidays.Reload(); err != nil {
+		log.Printf("Holiday calendar not loaded, only weekends use holiday timetable: %v", err)
+	}
+
+	// Aturan potongan tarif per kategori penumpang
+	fareRules := farerule.New(cfg.FareRulesFile)
+	if err := fareRules.Reload(); err != nil {
+		log.Printf("Fare rules not loaded, only rider_type=umum is available: %v", err)
+	}
+
+	stationUsecase := stationUsecase.NewUsecase(
+		stationService,
+		stationUsecase.WithLocation(cfg.Location),
+		stationUsecase.WithHolidayCalendar(holidays),
+		stationUsecase.WithServiceDayStart(cfg.ServiceDayStart),
+		stationUsecase.WithFareRules(fareRules),
+	)
+
+	// Isi cache lebih awal, sekaligus cek apakah data tersedia (dari upstream atau snapshot)
+	if err := warmUp(stationService); err != nil {
+		if cfg.OfflineMode {
+			log.Fatalf("offline mode: no usable snapshot in %s: %v", cfg.SnapshotDir, err)
+		}
+		log.Printf("Warm up failed, data will be fetched on first request: %v", err)
+	}
 
 	// Jalankan fungsi InitiateRoutes untuk memulai server
-	InitiateRoutes(stationUsecase, cfg.ServerPort)
+	InitiateRoutes(stationUsecase, stationService, upstream, holidays, fareRules, cfg.RequestTimeout, cfg.AdminToken, cfg.ServerPort)
+}
+
+// warmUp memanggil semua method service sekali saat server start.
+func warmUp(service station.Service) error {
+	ctx := context.Background()
+	if _, err := service.FetchStations(ctx); err != nil {
+		return err
+	}
+	if _, err := service.FetchSchedules(ctx); err != nil {
+		return err
+	}
+	if _, err := service.FetchFares(ctx); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // InitiateRoutes bertugas untuk:
 // 1. Membuat router baru (pakai Gin).
 // 2. Membuat group endpoint dengan prefix "/v1/api".
 // 3. Daftarkan semua route dari module station.
-// 4. Menjalankan server di port 8080.
-func InitiateRoutes(stationUsecase stationUsecase.Usecase, port string) {
+// 4. Daftarkan route admin dengan prefix "/v1/admin" (dilindungi ADMIN_TOKEN kalau di-set).
+// 5. Menjalankan server di port 8080.
+func InitiateRoutes(stationUsecase stationUsecase.Usecase, stationService *station.CachedService, upstream *client.Client, holidays *calendar.Calendar, fareRules *farerule.Engine, requestTimeout time.Duration, adminToken, port string) {
+	// router utama (sudah ada logger + recovery bawaan)
+	router := gin.Default()
+
+	// Setiap request punya request ID (header X-Request-ID), ikut dikirim di body response error.
+	// Harus dipasang sebelum group dibuat, karena group menyalin middleware saat dibuat.
+	router.Use(middleware.RequestID())
+	router.NoRoute(handler.RouteNotFound)
+
 	var (
-		router = gin.Default()           // router utama (sudah ada logger + recovery bawaan)
-		api    = router.Group("/v1/api") // prefix semua route diawali /v1/api
+		api   = router.Group("/v1/api")   // prefix semua route diawali /v1/api
+		admin = router.Group("/v1/admin") // prefix route operasional
 	)
 
+	// Setiap request API punya batas waktu, diteruskan sampai ke HTTP client upstream
+	api.Use(middleware.Deadline(requestTimeout))
+
 	// Daftarkan semua endpoint station ke dalam group /v1/api
 	handler.Initiate(api, stationUsecase)
 
+	// Endpoint admin selalu dipasang. Kalau ADMIN_TOKEN di-set, setiap request admin wajib membawa token itu;
+	// kalau kosong, endpoint admin terbuka seperti sebelumnya (cukup untuk development / jaringan internal).
+	if adminToken != "" {
+		admin.Use(handler.RequireAdminToken(adminToken))
+	} else {
+		log.Println("ADMIN_TOKEN is not set, admin endpoints are served without authentication")
+	}
+
+	// Daftarkan endpoint admin (statistik cache & upstream, kalender libur, aturan tarif, dll)
+	handler.InitiateAdmin(admin, stationService, upstream, holidays, fareRules)
+
 	// Jalankan server di port 8080
 	router.Run(":" + port)
 }
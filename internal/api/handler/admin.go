@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	stationService "github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/calendar"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/farerule"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/client"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdminToken menolak request yang tidak membawa header "Authorization: Bearer <token>"
+// dengan token yang cocok (401 UNAUTHORIZED). Token dibandingkan dengan waktu konstan
+// supaya isinya tidak bisa ditebak dari lama response.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		given, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			ctx.Header("WWW-Authenticate", "Bearer")
+			response.Fail(ctx, apperror.New(apperror.KindUnauthorized, apperror.CodeUnauthorized, "missing or invalid admin token"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// InitiateAdmin mendaftarkan endpoint untuk keperluan operasional (monitoring).
+func InitiateAdmin(router *gin.RouterGroup, cache *stationService.CachedService, upstream *client.Client, holidays *calendar.Calendar, fareRules *farerule.Engine) {
+
+	// GET /cache → statistik hit/miss cache service station
+	router.GET("/cache", func(ctx *gin.Context) {
+		GetCacheStats(ctx, cache)
+	})
+
+	// GET /upstream → statistik request ke API MRT (percobaan, retry, kegagalan) dan status circuit breaker
+	router.GET("/upstream", func(ctx *gin.Context) {
+		GetUpstreamStats(ctx, upstream)
+	})
+
+	// GET /data-quality → anomali data dari payload API MRT terakhir, dikelompokkan per stasiun
+	router.GET("/data-quality", func(ctx *gin.Context) {
+		GetDataQuality(ctx, cache)
+	})
+
+	// GET /holidays → daftar hari libur nasional yang sedang dipakai
+	router.GET("/holidays", func(ctx *gin.Context) {
+		GetHolidays(ctx, holidays)
+	})
+
+	// POST /holidays/reload → baca ulang file kalender libur tanpa restart server
+	router.POST("/holidays/reload", func(ctx *gin.Context) {
+		ReloadHolidays(ctx, holidays)
+	})
+
+	// GET /fare-rules → daftar kategori penumpang dan potongan tarifnya
+	router.GET("/fare-rules", func(ctx *gin.Context) {
+		GetFareRules(ctx, fareRules)
+	})
+
+	// POST /fare-rules/reload → baca ulang file aturan tarif tanpa restart server
+	router.POST("/fare-rules/reload", func(ctx *gin.Context) {
+		ReloadFareRules(ctx, fareRules)
+	})
+}
+
+func GetCacheStats(ctx *gin.Context, cache *stationService.CachedService) {
+	response.Success(ctx, cache.Stats())
+}
+
+// UpstreamOut (Output Statistik Client Upstream)
+type UpstreamOut struct {
+	Breaker client.BreakerState `json:"breaker"`
+	Retry   RetryPolicyOut      `json:"retry"`
+	Stats   client.Stats        `json:"stats"`
+}
+
+// RetryPolicyOut (Sub-struct Retry Policy yang Sedang Dipakai)
+type RetryPolicyOut struct {
+	MaxAttempts     int     `json:"max_attempts"`
+	BaseDelayMs     int64   `json:"base_delay_ms"`
+	MaxDelayMs      int64   `json:"max_delay_ms"`
+	Jitter          float64 `json:"jitter"`
+	RetryableStatus []int   `json:"retryable_status"`
+}
+
+func GetUpstreamStats(ctx *gin.Context, upstream *client.Client) {
+	policy := upstream.Policy()
+
+	response.Success(ctx, UpstreamOut{
+		Breaker: upstream.Breaker(),
+		Retry: RetryPolicyOut{
+			MaxAttempts:     policy.MaxAttempts,
+			BaseDelayMs:     policy.BaseDelay.Milliseconds(),
+			MaxDelayMs:      policy.MaxDelay.Milliseconds(),
+			Jitter:          policy.Jitter,
+			RetryableStatus: policy.RetryableStatus,
+		},
+		Stats: upstream.Stats(),
+	})
+}
+
+func GetDataQuality(ctx *gin.Context, cache *stationService.CachedService) {
+	response.Success(ctx, cache.DataQuality())
+}
+
+// HolidaysOut (Output Kalender Hari Libur)
+type HolidaysOut struct {
+	File      string             `json:"file"`
+	LoadedAt  string             `json:"loaded_at,omitempty"`
+	HariLibur []calendar.Holiday `json:"hari_libur"`
+}
+
+func GetHolidays(ctx *gin.Context, holidays *calendar.Calendar) {
+	resp := HolidaysOut{
+		File:      holidays.Path(),
+		HariLibur: holidays.List(),
+	}
+	if loadedAt := holidays.LoadedAt(); !loadedAt.IsZero() {
+		resp.LoadedAt = loadedAt.Format(time.RFC3339)
+	}
+
+	response.Success(ctx, resp)
+}
+
+func ReloadHolidays(ctx *gin.Context, holidays *calendar.Calendar) {
+	if err := holidays.Reload(); err != nil {
+		response.Fail(ctx, apperror.Wrap(apperror.KindInternal, apperror.CodeReloadFailed, fmt.Errorf("reload holiday calendar: %w", err)))
+		return
+	}
+
+	GetHolidays(ctx, holidays)
+}
+
+// FareRulesOut (Output Aturan Tarif per Kategori Penumpang)
+type FareRulesOut struct {
+	File              string               `json:"file"`
+	LoadedAt          string               `json:"loaded_at,omitempty"`
+	KategoriPenumpang []farerule.RiderType `json:"kategori_penumpang"`
+}
+
+func GetFareRules(ctx *gin.Context, fareRules *farerule.Engine) {
+	resp := FareRulesOut{
+		File:              fareRules.Path(),
+		KategoriPenumpang: fareRules.List(),
+	}
+	if loadedAt := fareRules.LoadedAt(); !loadedAt.IsZero() {
+		resp.LoadedAt = loadedAt.Format(time.RFC3339)
+	}
+
+	response.Success(ctx, resp)
+}
+
+func ReloadFareRules(ctx *gin.Context, fareRules *farerule.Engine) {
+	if err := fareRules.Reload(); err != nil {
+		response.Fail(ctx, apperror.Wrap(apperror.KindInternal, apperror.CodeReloadFailed, fmt.Errorf("reload fare rules: %w", err)))
+		return
+	}
+
+	GetFareRules(ctx, fareRules)
+}
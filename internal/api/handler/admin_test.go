@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/calendar"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/farerule"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{name: "valid token", token: "s3cret", header: "Bearer s3cret", wantStatus: http.StatusOK},
+		{name: "missing header", token: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", header: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "token prefix only", token: "s3cret", header: "Bearer s3c", wantStatus: http.StatusUnauthorized},
+		{name: "other scheme", token: "s3cret", header: "Basic s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "raw token without scheme", token: "s3cret", header: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "empty configured token rejects empty bearer", token: "", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequireAdminToken(tt.token))
+			router.POST("/holidays/reload", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/holidays/reload", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				ErrorCode string `json:"error_code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.ErrorCode != apperror.CodeUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("error_code = %q, WWW-Authenticate = %q, want %s and Bearer", resp.ErrorCode, rec.Header().Get("WWW-Authenticate"), apperror.CodeUnauthorized)
+			}
+		})
+	}
+}
+
+func TestReloadFailureHidesCause(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.yaml")
+	if err := os.WriteFile(broken, []byte("holidays: [unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.yaml")
+
+	tests := []struct {
+		name   string
+		path   string
+		reload func(ctx *gin.Context, path string)
+	}{
+		{name: "holidays missing file", path: missing, reload: func(ctx *gin.Context, path string) { ReloadHolidays(ctx, calendar.New(path)) }},
+		{name: "holidays broken yaml", path: broken, reload: func(ctx *gin.Context, path string) { ReloadHolidays(ctx, calendar.New(path)) }},
+		{name: "fare rules missing file", path: missing, reload: func(ctx *gin.Context, path string) { ReloadFareRules(ctx, farerule.New(path)) }},
+		{name: "fare rules broken yaml", path: broken, reload: func(ctx *gin.Context, path string) { ReloadFareRules(ctx, farerule.New(path)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+
+			tt.reload(ctx, tt.path)
+
+			var resp struct {
+				ErrorCode string `json:"error_code"`
+				Message   string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if rec.Code != http.StatusInternalServerError || resp.ErrorCode != apperror.CodeReloadFailed {
+				t.Fatalf("status = %d, error_code = %q, want 500 %s", rec.Code, resp.ErrorCode, apperror.CodeReloadFailed)
+			}
+			if strings.Contains(rec.Body.String(), dir) || resp.Message != "internal server error" {
+				t.Errorf("message = %q, want the cause hidden from the client", resp.Message)
+			}
+		})
+	}
+}
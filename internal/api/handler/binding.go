@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	stationIDPattern = regexp.MustCompile(`^[0-9]{1,10}$`)
+	clockPattern     = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+	registerOnce sync.Once
+)
+
+// registerValidators mendaftarkan tag validasi tambahan ke validator bawaan Gin:
+// - station_id  → ID stasiun berupa angka ("38").
+// - station_ids → daftar ID stasiun dipisah koma ("21,22,38").
+// - clock       → jam "HH:MM".
+// - oneofci     → seperti oneof, tapi tidak membedakan huruf besar/kecil.
+// Ditambah validasi antar-field untuk TripCostRequest (jumlah legs).
+// Nama field di error diambil dari tag uri/form/json, jadi sama dengan nama parameter di request.
+func registerValidators() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			panic("handler: gin validator engine is not go-playground/validator")
+		}
+
+		v.RegisterTagNameFunc(paramName)
+		mustRegister(v, "station_id", func(fl validator.FieldLevel) bool {
+			return stationIDPattern.MatchString(fl.Field().String())
+		})
+		mustRegister(v, "station_ids", func(fl validator.FieldLevel) bool {
+			for _, id := range strings.Split(fl.Field().String(), ",") {
+				if id = strings.TrimSpace(id); id != "" && !stationIDPattern.MatchString(id) {
+					return false
+				}
+			}
+			return true
+		})
+		mustRegister(v, "clock", func(fl validator.FieldLevel) bool {
+			return clockPattern.MatchString(fl.Field().String())
+		})
+		mustRegister(v, "oneofci", func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			for _, option := range strings.Fields(fl.Param()) {
+				if strings.EqualFold(value, option) {
+					return true
+				}
+			}
+			return false
+		})
+
+		v.RegisterStructValidation(validateTripCost, TripCostRequest{})
+	})
+}
+
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic("handler: register validation " + tag + ": " + err.Error())
+	}
+}
+
+// paramName mengembalikan nama parameter request untuk field struct (tag uri, form, lalu json).
+func paramName(field reflect.StructField) string {
+	for _, tag := range []string{"uri", "form", "json"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// bindRequest mengisi req dari path (tag uri) dan query string (tag form), lalu memvalidasinya sekali.
+// Kalau ada yang tidak valid, response 400 langsung dikirim dan hasilnya false.
+func bindRequest(ctx *gin.Context, req interface{}) bool {
+	params := make(map[string][]string, len(ctx.Params))
+	for _, p := range ctx.Params {
+		params[p.Key] = []string{p.Value}
+	}
+
+	err := binding.MapFormWithTag(req, params, "uri")
+	if err == nil {
+		err = binding.MapFormWithTag(req, ctx.Request.URL.Query(), "form")
+	}
+	if err == nil {
+		err = binding.Validator.ValidateStruct(req)
+	}
+	if err != nil {
+		response.Fail(ctx, bindError(err, false))
+		return false
+	}
+
+	return true
+}
+
+// bindBody mengisi dan memvalidasi req dari body JSON request.
+// Kalau ada yang tidak valid, response 400 langsung dikirim dan hasilnya false.
+func bindBody(ctx *gin.Context, req interface{}) bool {
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		response.Fail(ctx, bindError(err, true))
+		return false
+	}
+
+	return true
+}
+
+// bindError mengubah error binding Gin menjadi apperror:
+// - error validasi → VALIDATION_FAILED dengan details per field.
+// - body bukan JSON valid → INVALID_BODY.
+// - selain itu (misalnya tipe parameter salah) → INVALID_INPUT.
+func bindError(err error, withBody bool) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		if withBody {
+			return apperror.InvalidInput(apperror.CodeInvalidBody, "invalid request body: "+err.Error())
+		}
+		return apperror.InvalidInput(apperror.CodeInvalidInput, "invalid request: "+err.Error())
+	}
+
+	fields := make([]apperror.FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		field := fieldPath(fe)
+		fields = append(fields, apperror.FieldError{
+			Field:   field,
+			Code:    fieldErrorCode(fe),
+			Message: fieldErrorMessage(fe, field),
+		})
+	}
+
+	return apperror.Validation(fields...)
+}
+
+// fieldPath membuang nama struct di depan namespace validator ("TripCostRequest.legs[0].from" → "legs[0].from").
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+
+	return path
+}
+
+// tagCodes adalah kode error untuk tag validasi yang artinya sama di semua field.
+var tagCodes = map[string]string{
+	"station_id":  apperror.CodeInvalidStationID,
+	"station_ids": apperror.CodeInvalidStationID,
+	"nefield":     apperror.CodeSameStation,
+	"clock":       apperror.CodeInvalidTime,
+}
+
+// paramCodes adalah kode error per nama parameter, dipakai untuk tag umum (required, oneof, dll).
+var paramCodes = map[string]string{
+	"id":          apperror.CodeMissingStation,
+	"from":        apperror.CodeMissingStation,
+	"to":          apperror.CodeMissingStation,
+	"destination": apperror.CodeInvalidDest,
+	"day_type":    apperror.CodeInvalidDayType,
+	"mode":        apperror.CodeInvalidMode,
+	"limit":       apperror.CodeInvalidLimit,
+	"format":      apperror.CodeInvalidFormat,
+	"date":        apperror.CodeInvalidDate,
+	"month":       apperror.CodeInvalidMonth,
+	"rider_type":  apperror.CodeInvalidRiderType,
+	"at":          apperror.CodeInvalidAt,
+	"depart_at":   apperror.CodeInvalidAt,
+}
+
+func fieldErrorCode(fe validator.FieldError) string {
+	if code, ok := tagCodes[fe.Tag()]; ok {
+		return code
+	}
+	if fe.Field() == "legs" {
+		if fe.Tag() == "max" {
+			return apperror.CodeTooManyLegs
+		}
+		return apperror.CodeMissingLegs
+	}
+	if code, ok := paramCodes[fe.Field()]; ok {
+		return code
+	}
+
+	return apperror.CodeInvalidInput
+}
+
+func fieldErrorMessage(fe validator.FieldError, field string) string {
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "station_id":
+		return field + " must be a numeric station id"
+	case "station_ids":
+		return field + " must be a comma separated list of numeric station ids"
+	case "nefield":
+		return field + " must be different from " + siblingPath(field, fe.Param())
+	case "clock":
+		return "invalid " + field + ", use HH:MM"
+	case "number":
+		return "invalid " + field + ", must be a number"
+	case "oneof", "oneofci":
+		return "invalid " + field + ", use '" + strings.Join(strings.Fields(fe.Param()), "' or '") + "'"
+	case "datetime":
+		return "invalid " + field + ", use " + layoutLabel(fe.Param())
+	case "min":
+		if fe.Kind() == reflect.Slice && fe.Param() == "1" {
+			return field + " must not be empty"
+		}
+		return field + " must be at least " + fe.Param() + " characters"
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return field + " must contain at most " + fe.Param() + " items"
+		}
+		return field + " must be at most " + fe.Param() + " characters"
+	default:
+		return "invalid " + field
+	}
+}
+
+// siblingPath mengganti segmen terakhir path field dengan field pembanding ("legs[0].to", "From" → "legs[0].from").
+func siblingPath(field, other string) string {
+	other = strings.ToLower(other)
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		return field[:i+1] + other
+	}
+
+	return other
+}
+
+// layoutLabel mengubah layout tanggal Go ("2006-01-02") menjadi bentuk yang dikenal client ("YYYY-MM-DD").
+func layoutLabel(layout string) string {
+	return strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD").Replace(layout)
+}
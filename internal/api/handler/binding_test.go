@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestBindErrorFields(t *testing.T) {
+	registerValidators()
+
+	tooManyLegs := make([]TripLegRequest, 51)
+	for i := range tooManyLegs {
+		tooManyLegs[i] = TripLegRequest{From: "38", To: "21"}
+	}
+
+	tests := []struct {
+		name string
+		req  interface{}
+		want []apperror.FieldError // Message hanya dicek kalau diisi
+	}{
+		{
+			name: "station id must be numeric",
+			req:  &ScheduleRequest{ID: "abc"},
+			want: []apperror.FieldError{{Field: "id", Code: apperror.CodeInvalidStationID, Message: "id must be a numeric station id"}},
+		},
+		{
+			name: "required uses parameter code",
+			req:  &FareRequest{To: "21"},
+			want: []apperror.FieldError{{Field: "from", Code: apperror.CodeMissingStation, Message: "from is required"}},
+		},
+		{
+			name: "same station",
+			req:  &JourneyRequest{From: "21", To: "21"},
+			want: []apperror.FieldError{{Field: "to", Code: apperror.CodeSameStation, Message: "to must be different from from"}},
+		},
+		{
+			name: "oneofci lists options",
+			req:  &TimetableRequest{ID: "38", DayType: "minggu", Mode: "FULL"},
+			want: []apperror.FieldError{{Field: "day_type", Code: apperror.CodeInvalidDayType, Message: "invalid day_type, use 'biasa' or 'libur'"}},
+		},
+		{
+			name: "all invalid next-train fields reported together",
+			req:  &NextTrainRequest{ID: "38", Destination: "XX", Limit: "lots", From: "7pm"},
+			want: []apperror.FieldError{
+				{Field: "destination", Code: apperror.CodeInvalidDest, Message: "invalid destination, use 'LB' or 'HI'"},
+				{Field: "limit", Code: apperror.CodeInvalidLimit, Message: "invalid limit, must be a number"},
+				{Field: "from", Code: apperror.CodeInvalidTime, Message: "invalid from, use HH:MM"},
+			},
+		},
+		{
+			name: "window order is left to the usecase",
+			req:  &NextTrainRequest{ID: "38", Destination: "LB", From: "18:00", Until: "17:00"},
+		},
+		{
+			name: "station list",
+			req:  &FareMatrixRequest{Stations: "21,x", Format: "xml"},
+			want: []apperror.FieldError{
+				{Field: "stations", Code: apperror.CodeInvalidStationID},
+				{Field: "format", Code: apperror.CodeInvalidFormat},
+			},
+		},
+		{
+			name: "empty legs",
+			req:  &TripCostRequest{Legs: []TripLegRequest{}},
+			want: []apperror.FieldError{{Field: "legs", Code: apperror.CodeMissingLegs, Message: "legs must not be empty"}},
+		},
+		{
+			name: "too many legs uses MaxTripLegs",
+			req:  &TripCostRequest{Legs: tooManyLegs},
+			want: []apperror.FieldError{{Field: "legs", Code: apperror.CodeTooManyLegs, Message: "legs must contain at most 50 items"}},
+		},
+		{
+			name: "nested leg and projection paths",
+			req: &TripCostRequest{
+				Legs:       []TripLegRequest{{From: "38", To: "21"}, {From: "21", To: "21", Date: "02-11-2026"}},
+				Projection: &TripProjectionRequest{Month: "2026-13"},
+			},
+			want: []apperror.FieldError{
+				{Field: "legs[1].to", Code: apperror.CodeSameStation, Message: "legs[1].to must be different from legs[1].from"},
+				{Field: "legs[1].date", Code: apperror.CodeInvalidDate, Message: "invalid legs[1].date, use YYYY-MM-DD"},
+				{Field: "projection.month", Code: apperror.CodeInvalidMonth, Message: "invalid projection.month, use YYYY-MM"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.Validator.ValidateStruct(tt.req)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateStruct() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateStruct() error = nil, want validation error")
+			}
+
+			appErr, ok := apperror.As(bindError(err, false))
+			if !ok || appErr.Code != apperror.CodeValidationFailed {
+				t.Fatalf("bindError() = %v, want %s", appErr, apperror.CodeValidationFailed)
+			}
+			if len(appErr.Fields) != len(tt.want) {
+				t.Fatalf("fields = %+v, want %+v", appErr.Fields, tt.want)
+			}
+			for i, got := range appErr.Fields {
+				want := tt.want[i]
+				if got.Field != want.Field || got.Code != want.Code || (want.Message != "" && got.Message != want.Message) {
+					t.Errorf("field %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBindErrorNonValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		withBody bool
+		want     string
+	}{
+		{name: "query", withBody: false, want: apperror.CodeInvalidInput},
+		{name: "body", withBody: true, want: apperror.CodeInvalidBody},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := apperror.As(bindError(errors.New("boom"), tt.withBody))
+			if !ok || appErr.Code != tt.want || appErr.Kind != apperror.KindInvalidInput {
+				t.Errorf("bindError() = %v, want %s", appErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindRequestResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registerValidators()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantFields []string
+	}{
+		{name: "valid next-train", method: http.MethodGet, target: "/stations/38/next-train?destination=LB&limit=3", wantStatus: http.StatusOK},
+		{name: "path and query errors", method: http.MethodGet, target: "/stations/x/next-train?limit=a", wantStatus: http.StatusBadRequest, wantCode: apperror.CodeValidationFailed, wantFields: []string{"id", "destination", "limit"}},
+		{name: "valid body", method: http.MethodPost, target: "/trip-cost", body: `{"legs":[{"from":"38","to":"21"}]}`, wantStatus: http.StatusOK},
+		{name: "invalid json", method: http.MethodPost, target: "/trip-cost", body: `{"legs":`, wantStatus: http.StatusBadRequest, wantCode: apperror.CodeInvalidBody},
+		{name: "wrong json type", method: http.MethodPost, target: "/trip-cost", body: `{"legs":"38-21"}`, wantStatus: http.StatusBadRequest, wantCode: apperror.CodeInvalidBody},
+	}
+
+	router := gin.New()
+	router.GET("/stations/:id/next-train", func(ctx *gin.Context) {
+		if bindRequest(ctx, &NextTrainRequest{}) {
+			ctx.Status(http.StatusOK)
+		}
+	})
+	router.POST("/trip-cost", func(ctx *gin.Context) {
+		if bindBody(ctx, &TripCostRequest{}) {
+			ctx.Status(http.StatusOK)
+		}
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			var resp struct {
+				ErrorCode string `json:"error_code"`
+				Details   []struct {
+					Field string `json:"field"`
+				} `json:"details"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.ErrorCode != tt.wantCode {
+				t.Errorf("error_code = %q, want %q", resp.ErrorCode, tt.wantCode)
+			}
+			if len(resp.Details) != len(tt.wantFields) {
+				t.Fatalf("details = %+v, want fields %v", resp.Details, tt.wantFields)
+			}
+			for i, d := range resp.Details {
+				if d.Field != tt.wantFields[i] {
+					t.Errorf("details[%d].field = %q, want %q", i, d.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextTrainRequestLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit string
+		want  *int // nil = tidak dikirim, usecase memakai default
+	}{
+		{name: "absent", limit: ""},
+		{name: "zero is passed on", limit: "0", want: intPtr(0)},
+		{name: "padded zero is passed on", limit: "00", want: intPtr(0)},
+		{name: "number", limit: "5", want: intPtr(5)},
+		{name: "overflow is out of range", limit: "99999999999999999999", want: intPtr(station.MaxNextTrainLimit + 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextTrainRequest{ID: "38", Destination: "LB", Limit: tt.limit}.query().Limit
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("query().Limit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
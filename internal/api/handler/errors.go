@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetErrorCodes adalah handler untuk route GET /errors.
+// Mengembalikan katalog semua kode error beserta status HTTP dan deskripsinya.
+func GetErrorCodes(ctx *gin.Context) {
+	response.Success(ctx, response.Codes())
+}
+
+// GetErrorCode adalah handler untuk route GET /errors/:code (target doc_url di response error).
+func GetErrorCode(ctx *gin.Context) {
+	code := strings.ToUpper(ctx.Param("code"))
+
+	info, ok := response.Catalogue[code]
+	if !ok {
+		response.Fail(ctx, apperror.NotFound(apperror.CodeErrorCodeNotFound, "error code "+code+" not found"))
+		return
+	}
+
+	response.Success(ctx, info)
+}
+
+// RouteNotFound dipakai sebagai NoRoute handler, supaya endpoint yang tidak ada
+// juga dibalas dengan format error yang sama.
+func RouteNotFound(ctx *gin.Context) {
+	response.Fail(ctx, apperror.NotFound(apperror.CodeRouteNotFound, "route "+ctx.Request.URL.Path+" not found"))
+}
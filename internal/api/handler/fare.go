@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetFareMatrix adalah handler untuk route GET /fares/matrix.
+// - ?stations=21,22,38 → hanya stasiun tertentu.
+// - ?format=csv        → response berupa file CSV (satu baris per pasangan asal–tujuan).
+func GetFareMatrix(ctx *gin.Context, usecase station.Usecase) {
+	var req FareMatrixRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+
+	resp, err := usecase.GetFareMatrix(ctx.Request.Context(), req.query())
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	if strings.EqualFold(req.Format, "csv") {
+		body, err := fareMatrixCSV(resp)
+		if err != nil {
+			response.Fail(ctx, err)
+			return
+		}
+		ctx.Header("Content-Disposition", `attachment; filename="fare-matrix.csv"`)
+		ctx.Data(http.StatusOK, "text/csv; charset=utf-8", body)
+		return
+	}
+
+	success(ctx, resp)
+}
+
+// fareMatrixCSV mengubah matriks tarif menjadi CSV dengan satu baris per pasangan asal–tujuan.
+func fareMatrixCSV(matrix *station.FareMatrixOut) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"id_dari", "dari", "id_ke", "ke", "tarif_rupiah", "mata_uang", "durasi_menit"})
+	for i, from := range matrix.Stasiun {
+		for j, to := range matrix.Stasiun {
+			cell := matrix.Matriks[i][j]
+			if cell == nil {
+				continue
+			}
+			w.Write([]string{
+				from.Id,
+				from.Nama,
+				to.Id,
+				to.Nama,
+				strconv.FormatInt(cell.TarifRupiah, 10),
+				matrix.MataUang,
+				strconv.Itoa(cell.DurasiMenit),
+			})
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// EstimateTripCost adalah handler untuk route POST /fares/trip-cost.
+// Body JSON berisi daftar leg (from, to, date opsional), rider_type opsional,
+// dan projection opsional ({"month": "2026-11", "day_type": "biasa"}).
+func EstimateTripCost(ctx *gin.Context, usecase station.Usecase) {
+	var req TripCostRequest
+	if !bindBody(ctx, &req) {
+		return
+	}
+
+	resp, err := usecase.EstimateTripCost(ctx.Request.Context(), req.query())
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PlanJourney adalah handler untuk route GET /journeys?from=&to=&depart_at=.
+// Menggabungkan tarif, arah kereta, dan keberangkatan berikutnya dalam satu response.
+func PlanJourney(ctx *gin.Context, usecase station.Usecase) {
+	var req JourneyRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+
+	resp, err := usecase.PlanJourney(ctx.Request.Context(), req.query())
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
+}
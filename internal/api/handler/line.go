@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLines adalah handler untuk route GET /lines.
+func GetLines(ctx *gin.Context, usecase station.Usecase) {
+	resp, err := usecase.GetLines(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
+}
+
+// GetLineStations adalah handler untuk route GET /lines/:id/stations.
+func GetLineStations(ctx *gin.Context, usecase station.Usecase) {
+	id := ctx.Param("id")
+
+	resp, err := usecase.GetLineStations(ctx.Request.Context(), id)
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
+}
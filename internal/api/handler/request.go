@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
+	"github.com/go-playground/validator/v10"
+)
+
+// Request DTO untuk endpoint station.
+// Setiap DTO di-bind dari path (uri), query string (form), atau body (json) lewat bindRequest,
+// jadi input yang salah format langsung dibalas 400 sebelum usecase (dan API MRT) dipanggil.
+// Aturan yang bergantung pada data (ID stasiun ada atau tidak, kategori penumpang, dll)
+// tetap dicek di usecase.
+
+// StationListRequest (Parameter GET /stations)
+type StationListRequest struct {
+	Name string `form:"name" binding:"max=100"`
+}
+
+// ScheduleRequest (Parameter GET /stations/:id)
+type ScheduleRequest struct {
+	ID      string `uri:"id" binding:"required,station_id"`
+	At      string `form:"at" binding:"max=64"`
+	DayType string `form:"day_type" binding:"omitempty,oneofci=biasa libur"`
+}
+
+func (r ScheduleRequest) query() station.ScheduleQuery {
+	return station.ScheduleQuery{ID: r.ID, At: r.At, DayType: r.DayType}
+}
+
+// TimetableRequest (Parameter GET /stations/:id/timetable)
+type TimetableRequest struct {
+	ID      string `uri:"id" binding:"required,station_id"`
+	At      string `form:"at" binding:"max=64"`
+	DayType string `form:"day_type" binding:"omitempty,oneofci=biasa libur"`
+	Mode    string `form:"mode" binding:"omitempty,oneofci=remaining full"`
+}
+
+func (r TimetableRequest) query() station.TimetableQuery {
+	return station.TimetableQuery{ID: r.ID, At: r.At, DayType: r.DayType, Mode: r.Mode}
+}
+
+// NextTrainRequest (Parameter GET /stations/:id/next-train)
+// Limit di-bind sebagai string supaya nilai yang bukan angka tetap dilaporkan per field;
+// rentang 1 - MaxNextTrainLimit dan urutan from/until (yang bergantung pada hari operasional) dicek di usecase.
+type NextTrainRequest struct {
+	ID          string `uri:"id" binding:"required,station_id"`
+	Destination string `form:"destination" binding:"required,oneof=LB HI"`
+	At          string `form:"at" binding:"max=64"`
+	DayType     string `form:"day_type" binding:"omitempty,oneofci=biasa libur"`
+	Limit       string `form:"limit" binding:"omitempty,number"`
+	From        string `form:"from" binding:"omitempty,clock"`
+	Until       string `form:"until" binding:"omitempty,clock"`
+}
+
+func (r NextTrainRequest) query() station.NextTrainQuery {
+	query := station.NextTrainQuery{
+		ID:          r.ID,
+		Destination: r.Destination,
+		At:          r.At,
+		DayType:     r.DayType,
+		From:        r.From,
+		Until:       r.Until,
+	}
+
+	// limit yang dikirim (termasuk 0) diteruskan apa adanya supaya ditolak usecase, bukan diganti default
+	if r.Limit != "" {
+		limit, err := strconv.Atoi(r.Limit)
+		if err != nil {
+			// Angka terlalu besar untuk int, tetap harus ditolak sebagai limit di luar batas
+			limit = station.MaxNextTrainLimit + 1
+		}
+		query.Limit = &limit
+	}
+
+	return query
+}
+
+// StationIDRequest (Parameter GET /stations/:id/details)
+type StationIDRequest struct {
+	ID string `uri:"id" binding:"required,station_id"`
+}
+
+// FareRequest (Parameter GET /stations/fare)
+type FareRequest struct {
+	From      string `form:"from" binding:"required,station_id"`
+	To        string `form:"to" binding:"required,station_id,nefield=From"`
+	RiderType string `form:"rider_type" binding:"max=32"`
+}
+
+func (r FareRequest) query() station.FareQuery {
+	return station.FareQuery{From: r.From, To: r.To, RiderType: r.RiderType}
+}
+
+// JourneyRequest (Parameter GET /journeys)
+type JourneyRequest struct {
+	From     string `form:"from" binding:"required,station_id"`
+	To       string `form:"to" binding:"required,station_id,nefield=From"`
+	DepartAt string `form:"depart_at" binding:"max=64"`
+}
+
+func (r JourneyRequest) query() station.JourneyQuery {
+	return station.JourneyQuery{From: r.From, To: r.To, DepartAt: r.DepartAt}
+}
+
+// FareMatrixRequest (Parameter GET /fares/matrix)
+type FareMatrixRequest struct {
+	Stations string `form:"stations" binding:"omitempty,station_ids"`
+	Format   string `form:"format" binding:"omitempty,oneofci=json csv"`
+}
+
+func (r FareMatrixRequest) query() station.FareMatrixQuery {
+	var query station.FareMatrixQuery
+	for _, id := range strings.Split(r.Stations, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			query.StationIDs = append(query.StationIDs, id)
+		}
+	}
+
+	return query
+}
+
+// TripCostRequest (Body POST /fares/trip-cost)
+// Jumlah legs dibatasi MaxTripLegs lewat validateTripCost, bukan tag max, supaya batasnya tetap sama dengan usecase.
+type TripCostRequest struct {
+	RiderType  string                 `json:"rider_type" binding:"max=32"`
+	Legs       []TripLegRequest       `json:"legs" binding:"required,min=1,dive"`
+	Projection *TripProjectionRequest `json:"projection"`
+}
+
+// TripLegRequest (Satu Perjalanan dalam TripCostRequest)
+type TripLegRequest struct {
+	From string `json:"from" binding:"required,station_id"`
+	To   string `json:"to" binding:"required,station_id,nefield=From"`
+	Date string `json:"date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// TripProjectionRequest (Proyeksi Bulanan dalam TripCostRequest)
+type TripProjectionRequest struct {
+	Month   string `json:"month" binding:"required,datetime=2006-01"`
+	DayType string `json:"day_type" binding:"omitempty,oneofci=biasa libur"`
+}
+
+// validateTripCost menolak body dengan legs lebih dari MaxTripLegs (dilaporkan sebagai tag max pada field legs).
+func validateTripCost(sl validator.StructLevel) {
+	r := sl.Current().Interface().(TripCostRequest)
+	if len(r.Legs) > station.MaxTripLegs {
+		sl.ReportError(r.Legs, "legs", "Legs", "max", strconv.Itoa(station.MaxTripLegs))
+	}
+}
+
+func (r TripCostRequest) query() station.TripCostQuery {
+	query := station.TripCostQuery{
+		RiderType: r.RiderType,
+		Legs:      make([]station.TripLegQuery, 0, len(r.Legs)),
+	}
+	for _, leg := range r.Legs {
+		query.Legs = append(query.Legs, station.TripLegQuery{From: leg.From, To: leg.To, Date: leg.Date})
+	}
+	if r.Projection != nil {
+		query.Projection = &station.TripProjectionQuery{Month: r.Projection.Month, DayType: r.Projection.DayType}
+	}
+
+	return query
+}
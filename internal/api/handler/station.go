@@ -1,6 +1,7 @@
 package handler
 
 import (
+	stationService "github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
 	"github.com/IkrmMrbsy/mrt-schedules/internal/api/usecase/station"
 	"github.com/IkrmMrbsy/mrt-schedules/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -9,7 +10,14 @@ import (
 // Initiate digunakan untuk mendaftarkan semua route (endpoint) terkait station.
 // - Pertama buat service station (pakai NewService).
 // - Lalu daftarkan route /stations GET yang akan memanggil fungsi GetAllStation.
+// - Umur data yang dipakai setiap request dicatat di context request, lalu ditandai di response sukses.
+// - Validasi parameter request (lihat request.go) didaftarkan sekali ke validator Gin.
 func Initiate(router *gin.RouterGroup, usecase station.Usecase) {
+	registerValidators()
+
+	router.Use(func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(stationService.WithSnapshotUsage(ctx.Request.Context()))
+	})
 
 	// Buat group route "/stations"
 	station := router.Group("/stations")
@@ -31,9 +39,44 @@ func Initiate(router *gin.RouterGroup, usecase station.Usecase) {
 		GetNextTrainByStation(ctx, usecase)
 	})
 
+	station.GET("/:id/timetable", func(ctx *gin.Context) {
+		GetTimetableByStation(ctx, usecase)
+	})
+
 	station.GET("/:id/details", func(ctx *gin.Context) {
 		GetStationDetails(ctx, usecase)
 	})
+
+	// GET /journeys
+	router.GET("/journeys", func(ctx *gin.Context) {
+		PlanJourney(ctx, usecase)
+	})
+
+	// Buat group route "/fares"
+	fare := router.Group("/fares")
+
+	fare.GET("/matrix", func(ctx *gin.Context) {
+		GetFareMatrix(ctx, usecase)
+	})
+
+	fare.POST("/trip-cost", func(ctx *gin.Context) {
+		EstimateTripCost(ctx, usecase)
+	})
+
+	// GET /errors → katalog kode error, GET /errors/:code → detail satu kode (target doc_url)
+	router.GET("/errors", GetErrorCodes)
+	router.GET("/errors/:code", GetErrorCode)
+
+	// Buat group route "/lines"
+	line := router.Group("/lines")
+
+	line.GET("", func(ctx *gin.Context) {
+		GetLines(ctx, usecase)
+	})
+
+	line.GET("/:id/stations", func(ctx *gin.Context) {
+		GetLineStations(ctx, usecase)
+	})
 }
 
 // GetAllStation adalah handler untuk route GET /stations.
@@ -42,64 +85,103 @@ func Initiate(router *gin.RouterGroup, usecase station.Usecase) {
 // 2. Kalau error, balikin response 400 (Bad Request).
 // 3. Kalau sukses, balikin response 200 (OK) beserta data stasiun.
 func GetAllStation(ctx *gin.Context, usecase station.Usecase) {
-	name := ctx.Query("name")
+	var req StationListRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
 
-	resp, err := usecase.GetAllStation(name)
+	resp, err := usecase.GetAllStation(ctx.Request.Context(), req.Name)
 	if err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Fail(ctx, err)
 		return
 	}
 
 	// Jika sukses, kembalikan HTTP 200 dengan data stasiun
-	response.Success(ctx, resp)
+	success(ctx, resp)
 }
 
 func CheckScheduleByStation(ctx *gin.Context, usecase station.Usecase) {
-	id := ctx.Param("id")
+	var req ScheduleRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
 
-	resp, err := usecase.CheckScheduleByStation(id)
+	resp, err := usecase.CheckScheduleByStation(ctx.Request.Context(), req.query())
 	if err != nil {
-		response.NotFound(ctx, err.Error())
+		response.Fail(ctx, err)
 		return
 	}
 
-	response.Success(ctx, resp)
+	success(ctx, resp)
+}
+
+func GetTimetableByStation(ctx *gin.Context, usecase station.Usecase) {
+	var req TimetableRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+
+	resp, err := usecase.GetTimetableByStation(ctx.Request.Context(), req.query())
+	if err != nil {
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
 }
 
 func GetFareAndDuration(ctx *gin.Context, usecase station.Usecase) {
-	fromId := ctx.Query("from")
-	toId := ctx.Query("to")
+	var req FareRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
 
-	resp, err := usecase.GetFareAndDuration(fromId, toId)
+	resp, err := usecase.GetFareAndDuration(ctx.Request.Context(), req.query())
 	if err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Fail(ctx, err)
 		return
 	}
 
-	response.Success(ctx, resp)
+	success(ctx, resp)
 }
 
 func GetNextTrainByStation(ctx *gin.Context, usecase station.Usecase) {
-	id := ctx.Param("id")
-	destination := ctx.Query("destination")
+	var req NextTrainRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
 
-	resp, err := usecase.GetNextTrainByStation(id, destination)
+	resp, err := usecase.GetNextTrainByStation(ctx.Request.Context(), req.query())
 	if err != nil {
-		response.NotFound(ctx, err.Error())
+		response.Fail(ctx, err)
 		return
 	}
 
-	response.Success(ctx, resp)
+	success(ctx, resp)
 }
 
 func GetStationDetails(ctx *gin.Context, usecase station.Usecase) {
-	id := ctx.Param("id")
+	var req StationIDRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
 
-	resp, err := usecase.GetStationDetails(id)
+	resp, err := usecase.GetStationDetails(ctx.Request.Context(), req.ID)
 	if err != nil {
-		response.NotFound(ctx, err.Error())
+		response.Fail(ctx, err)
+		return
+	}
+
+	success(ctx, resp)
+}
+
+// success mengirim response 200 beserta umur data yang dipakai request ini (kalau tercatat).
+func success(ctx *gin.Context, data interface{}) {
+	info, ok := stationService.SnapshotUsed(ctx.Request.Context())
+	if !ok {
+		response.Success(ctx, data)
 		return
 	}
 
-	response.Success(ctx, resp)
+	response.SuccessWithSnapshot(ctx, data, info.FetchedAt, info.Stale)
 }
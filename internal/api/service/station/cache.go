@@ -0,0 +1,356 @@
+package station
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheTTL menampung lama cache untuk tiap method Service.
+// TTL 0 artinya data tidak disimpan (tiap panggilan tetap ke upstream),
+// tapi request yang datang bersamaan tetap digabung jadi satu.
+//
+// StaleWindow adalah batas waktu setelah TTL habis di mana data lama masih
+// langsung dikembalikan sambil di-refresh di background (stale-while-revalidate).
+// Lewat dari itu, request akan menunggu fetch baru. Kalau fetch gagal,
+// snapshot terakhir tetap dipakai berapapun umurnya (serve-stale-on-error).
+type CacheTTL struct {
+	Stations    time.Duration
+	Schedules   time.Duration
+	Fares       time.Duration
+	StaleWindow time.Duration
+}
+
+// CacheStats adalah statistik pemakaian cache untuk satu method.
+// - Hits        → data segar diambil dari cache.
+// - Misses      → cache kosong/kadaluarsa, harus ambil ke upstream.
+// - Coalesced   → ikut menunggu fetch yang sedang berjalan (tidak bikin request baru).
+// - StaleServed → data lama dikembalikan (sedang revalidate atau upstream error).
+// - FetchErrors → fetch ke upstream yang gagal.
+type CacheStats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Coalesced   uint64 `json:"coalesced"`
+	StaleServed uint64 `json:"stale_served"`
+	FetchErrors uint64 `json:"fetch_errors"`
+}
+
+// SnapshotInfo menjelaskan umur data yang disajikan untuk satu request.
+// Stale bernilai true kalau ada data yang sudah lewat TTL atau berasal dari snapshot lama.
+type SnapshotInfo struct {
+	FetchedAt time.Time
+	Stale     bool
+}
+
+type snapshotUsageKey struct{}
+
+// snapshotUsage mengumpulkan umur resource yang benar-benar dipakai selama satu request.
+type snapshotUsage struct {
+	mu   sync.Mutex
+	info SnapshotInfo
+	used bool
+}
+
+// WithSnapshotUsage menyiapkan ctx supaya CachedService mencatat umur setiap resource yang dikembalikan.
+// Hasilnya dibaca lewat SnapshotUsed setelah usecase selesai.
+func WithSnapshotUsage(ctx context.Context) context.Context {
+	return context.WithValue(ctx, snapshotUsageKey{}, &snapshotUsage{})
+}
+
+// SnapshotUsed mengembalikan umur data yang dipakai selama request:
+// FetchedAt dari resource paling tua, Stale true kalau salah satu resource stale.
+// ok bernilai false kalau ctx tidak disiapkan lewat WithSnapshotUsage atau belum ada resource yang dipakai.
+func SnapshotUsed(ctx context.Context) (info SnapshotInfo, ok bool) {
+	usage, _ := ctx.Value(snapshotUsageKey{}).(*snapshotUsage)
+	if usage == nil {
+		return SnapshotInfo{}, false
+	}
+
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	return usage.info, usage.used
+}
+
+// trackSnapshot mencatat umur satu resource ke ctx (kalau disiapkan lewat WithSnapshotUsage).
+func trackSnapshot(ctx context.Context, fetchedAt time.Time, stale bool) {
+	usage, _ := ctx.Value(snapshotUsageKey{}).(*snapshotUsage)
+	if usage == nil || fetchedAt.IsZero() {
+		return
+	}
+
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	if !usage.used || fetchedAt.Before(usage.info.FetchedAt) {
+		usage.info.FetchedAt = fetchedAt
+	}
+	usage.info.Stale = usage.info.Stale || stale
+	usage.used = true
+}
+
+// CachedService adalah decorator untuk Service.
+// Hasil FetchStations, FetchSchedules dan FetchFares disimpan di memory sesuai TTL,
+// jadi upstream API MRT tidak dipanggil di setiap request.
+// Data yang dikembalikan dipakai bersama, jadi pemanggil tidak boleh mengubah isinya.
+type CachedService struct {
+	next      Service
+	stations  *cachedResource[[]StationIn]
+	schedules *cachedResource[[]ScheduleIn]
+	fares     *cachedResource[[]FareIn]
+
+	fetchStations  func(context.Context) ([]StationIn, SnapshotInfo, error)
+	fetchSchedules func(context.Context) ([]ScheduleIn, SnapshotInfo, error)
+	fetchFares     func(context.Context) ([]FareIn, SnapshotInfo, error)
+}
+
+// NewCachedService membungkus service asli dengan cache in-memory.
+// Umur cache (TTL dan stale window) selalu dihitung dari waktu data dimuat ke memory.
+// Kalau next bisa melaporkan waktu fetch asli datanya (misalnya data dari snapshot di disk),
+// waktu itu hanya dipakai untuk header X-Snapshot-Age dan penanda stale.
+func NewCachedService(next Service, ttl CacheTTL) *CachedService {
+	c := &CachedService{
+		next:      next,
+		stations:  &cachedResource[[]StationIn]{name: "stations", ttl: ttl.Stations, staleWindow: ttl.StaleWindow},
+		schedules: &cachedResource[[]ScheduleIn]{name: "schedules", ttl: ttl.Schedules, staleWindow: ttl.StaleWindow},
+		fares:     &cachedResource[[]FareIn]{name: "fares", ttl: ttl.Fares, staleWindow: ttl.StaleWindow},
+
+		fetchStations:  undated(next.FetchStations),
+		fetchSchedules: undated(next.FetchSchedules),
+		fetchFares:     undated(next.FetchFares),
+	}
+	if dated, ok := next.(datedService); ok {
+		c.fetchStations = dated.fetchStations
+		c.fetchSchedules = dated.fetchSchedules
+		c.fetchFares = dated.fetchFares
+	}
+
+	return c
+}
+
+// undated menganggap data dari fetch diambil dari upstream tepat saat fetch selesai.
+func undated[T any](fetch func(context.Context) (T, error)) func(context.Context) (T, SnapshotInfo, error) {
+	return func(ctx context.Context) (T, SnapshotInfo, error) {
+		value, err := fetch(ctx)
+		return value, SnapshotInfo{FetchedAt: time.Now()}, err
+	}
+}
+
+func (c *CachedService) FetchStations(ctx context.Context) ([]StationIn, error) {
+	return c.stations.get(ctx, c.fetchStations)
+}
+
+func (c *CachedService) FetchSchedules(ctx context.Context) ([]ScheduleIn, error) {
+	return c.schedules.get(ctx, c.fetchSchedules)
+}
+
+func (c *CachedService) FetchFares(ctx context.Context) ([]FareIn, error) {
+	return c.fares.get(ctx, c.fetchFares)
+}
+
+// Stats mengembalikan jumlah hit/miss per method.
+func (c *CachedService) Stats() map[string]CacheStats {
+	return map[string]CacheStats{
+		"stations":  c.stations.stats(),
+		"schedules": c.schedules.stats(),
+		"fares":     c.fares.stats(),
+	}
+}
+
+// DataQuality meneruskan laporan kualitas data dari service asli (kosong kalau tidak didukung).
+// Laporannya mengikuti fetch terakhir ke upstream, bukan setiap cache hit.
+func (c *CachedService) DataQuality() QualityReport {
+	if reporter, ok := c.next.(QualityReporter); ok {
+		return reporter.DataQuality()
+	}
+
+	return QualityReport{Healthy: true, Resources: []ResourceQuality{}, Stations: []StationQuality{}}
+}
+
+// cachedResource menyimpan satu jenis data beserta waktu fetch-nya.
+// Kalau ada beberapa request bersamaan saat cache kosong, hanya satu
+// yang benar-benar memanggil upstream, sisanya menunggu hasil yang sama.
+// Fetch bersama punya context sendiri (value dari request tetap dibawa):
+//   - Request yang menunggu dihitung sebagai waiter; request yang ctx-nya selesai berhenti menunggu.
+//   - Kalau waiter terakhir pergi, context fetch dibatalkan, jadi request HTTP dan retry ke upstream ikut berhenti.
+//   - Refresh di background (stale-while-revalidate) tidak punya waiter dan tidak dibatalkan;
+//     batas waktunya mengikuti timeout http.Client dan retry policy.
+type cachedResource[T any] struct {
+	name        string
+	ttl         time.Duration
+	staleWindow time.Duration
+
+	mu        sync.Mutex
+	value     T
+	loadedAt  time.Time // waktu value dimuat ke cache, dasar perhitungan TTL
+	fetchedAt time.Time // waktu value diambil dari upstream (untuk snapshot: waktu snapshot dibuat)
+	stale     bool      // value berasal dari snapshot lama, bukan dari upstream
+	hasValue  bool
+	call      *inflightCall[T]
+
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	coalesced   atomic.Uint64
+	staleServed atomic.Uint64
+	fetchErrors atomic.Uint64
+}
+
+// inflightCall mewakili fetch ke upstream yang sedang berjalan.
+// waiters dan detached hanya diubah saat cachedResource.mu terkunci.
+type inflightCall[T any] struct {
+	done      chan struct{}
+	value     T
+	fetchedAt time.Time
+	stale     bool
+	err       error
+
+	cancel   context.CancelFunc
+	waiters  int
+	detached bool // refresh background, tidak dibatalkan walaupun tidak ada waiter
+}
+
+// get mengembalikan data dari cache atau upstream, lalu mencatat umurnya ke ctx (lihat WithSnapshotUsage).
+// Kalau ttl 0, cache dilewati sepenuhnya: tidak ada hit, tidak ada stale window, dan tidak ada fallback ke data lama.
+func (r *cachedResource[T]) get(ctx context.Context, fetch func(context.Context) (T, SnapshotInfo, error)) (T, error) {
+	r.mu.Lock()
+
+	if r.hasValue && r.ttl > 0 {
+		age := time.Since(r.loadedAt)
+
+		// Cache masih segar → langsung pakai
+		if age < r.ttl {
+			value, fetchedAt, stale := r.value, r.fetchedAt, r.stale
+			r.mu.Unlock()
+			r.hits.Add(1)
+			trackSnapshot(ctx, fetchedAt, stale)
+			return value, nil
+		}
+
+		// Sudah kadaluarsa tapi masih dalam stale window →
+		// kembalikan data lama, refresh jalan di background
+		if age < r.ttl+r.staleWindow {
+			value, fetchedAt := r.value, r.fetchedAt
+			r.startFetchLocked(ctx, fetch, false)
+			r.mu.Unlock()
+			r.staleServed.Add(1)
+			trackSnapshot(ctx, fetchedAt, true)
+			return value, nil
+		}
+	}
+
+	// Sudah ada fetch yang berjalan → tunggu hasilnya saja
+	if r.call != nil {
+		r.coalesced.Add(1)
+	} else {
+		r.misses.Add(1)
+	}
+	call := r.startFetchLocked(ctx, fetch, true)
+	r.mu.Unlock()
+
+	var err error
+	select {
+	case <-call.done:
+		if call.err == nil {
+			trackSnapshot(ctx, call.fetchedAt, call.stale)
+			return call.value, nil
+		}
+		err = call.err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// Upstream gagal atau waktu request habis → pakai snapshot terakhir kalau ada
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leaveLocked(call)
+	if r.hasValue && r.ttl > 0 {
+		r.staleServed.Add(1)
+		trackSnapshot(ctx, r.fetchedAt, true)
+		return r.value, nil
+	}
+
+	var zero T
+	return zero, err
+}
+
+// startFetchLocked memulai fetch ke upstream di goroutine terpisah,
+// atau mengembalikan fetch yang sedang berjalan. Harus dipanggil saat r.mu terkunci.
+// Kalau wait true, pemanggil dihitung sebagai waiter dan wajib memanggil leaveLocked setelah selesai menunggu;
+// kalau false, fetch dianggap refresh background dan tidak akan dibatalkan.
+// Value dari ctx (misalnya request ID) tetap dibawa. Pembatalan dan deadline ctx tidak diwariskan langsung,
+// karena fetch dipakai bersama; fetch berhenti saat waiter terakhir pergi (lihat leaveLocked).
+func (r *cachedResource[T]) startFetchLocked(ctx context.Context, fetch func(context.Context) (T, SnapshotInfo, error), wait bool) *inflightCall[T] {
+	call := r.call
+	if call == nil {
+		call = &inflightCall[T]{done: make(chan struct{})}
+		r.call = call
+
+		shared, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call.cancel = cancel
+		go r.run(shared, call, fetch)
+	}
+
+	if wait {
+		call.waiters++
+	} else {
+		call.detached = true
+	}
+
+	return call
+}
+
+// leaveLocked mencatat bahwa satu waiter berhenti menunggu call.
+// Kalau tidak ada lagi yang menunggu, fetch dibatalkan dan call dilepas supaya
+// request berikutnya memulai fetch baru. Harus dipanggil saat r.mu terkunci.
+func (r *cachedResource[T]) leaveLocked(call *inflightCall[T]) {
+	call.waiters--
+	if call.waiters > 0 || call.detached {
+		return
+	}
+
+	call.cancel()
+	if r.call == call {
+		r.call = nil
+	}
+}
+
+// run menjalankan fetch untuk call lalu menyimpan hasilnya ke cache.
+func (r *cachedResource[T]) run(ctx context.Context, call *inflightCall[T], fetch func(context.Context) (T, SnapshotInfo, error)) {
+	defer call.cancel()
+	var info SnapshotInfo
+	call.value, info, call.err = fetch(ctx)
+	// Data dari snapshot di disk langsung dianggap stale, walaupun umurnya masih di bawah TTL
+	call.fetchedAt, call.stale = info.FetchedAt, info.Stale
+
+	r.mu.Lock()
+	switch {
+	case call.err == nil && r.ttl > 0:
+		r.value = call.value
+		r.loadedAt = time.Now()
+		r.fetchedAt = call.fetchedAt
+		r.stale = call.stale
+		r.hasValue = true
+	case call.err == nil:
+		// TTL 0 → hasil tidak disimpan
+	case ctx.Err() != nil:
+		// Dibatalkan karena semua waiter sudah pergi, bukan kegagalan upstream
+	default:
+		r.fetchErrors.Add(1)
+		log.Printf("cache: refresh %s failed: %v", r.name, call.err)
+	}
+	if r.call == call {
+		r.call = nil
+	}
+	r.mu.Unlock()
+	close(call.done)
+}
+
+func (r *cachedResource[T]) stats() CacheStats {
+	return CacheStats{
+		Hits:        r.hits.Load(),
+		Misses:      r.misses.Load(),
+		Coalesced:   r.coalesced.Load(),
+		StaleServed: r.staleServed.Load(),
+		FetchErrors: r.fetchErrors.Load(),
+	}
+}
@@ -0,0 +1,305 @@
+package station
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/snapshot"
+)
+
+func TestCachedServiceOfflineSnapshot(t *testing.T) {
+	store, err := snapshot.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	savedAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	if err := store.Save(ResourceStations, []byte(`[{"nid":"38","title":"Lebak Bulus"},{"nid":"21","title":"Bundaran HI"}]`), savedAt); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	svc := NewService(time.Second, "http://127.0.0.1:0/unused", WithSnapshotStore(store), WithOfflineMode(true))
+	cached := NewCachedService(svc, CacheTTL{Stations: 5 * time.Minute, StaleWindow: 10 * time.Minute})
+
+	for i := 0; i < 5; i++ {
+		ctx := WithSnapshotUsage(context.Background())
+		stations, err := cached.FetchStations(ctx)
+		if err != nil {
+			t.Fatalf("call %d: FetchStations() error = %v", i, err)
+		}
+		if len(stations) != 2 {
+			t.Fatalf("call %d: got %d stations, want 2", i, len(stations))
+		}
+
+		// Umur yang dilaporkan tetap umur snapshot, walaupun cache-nya masih segar
+		info, ok := SnapshotUsed(ctx)
+		if !ok || !info.FetchedAt.Equal(savedAt) || !info.Stale {
+			t.Errorf("call %d: SnapshotUsed() = %+v, %v, want FetchedAt %v and stale", i, info, ok, savedAt)
+		}
+	}
+
+	stats := cached.Stats()["stations"]
+	if stats.Misses != 1 || stats.Hits != 4 {
+		t.Errorf("stats = %+v, want 1 miss and 4 hits", stats)
+	}
+}
+
+// countingService adalah Service palsu yang menghitung berapa kali FetchStations dipanggil.
+// errs[i] dikembalikan untuk panggilan ke-i (nil = sukses); kalau gate di-set, fetch menunggu gate ditutup.
+type countingService struct {
+	mu    sync.Mutex
+	calls int
+	errs  []error
+	gate  chan struct{}
+
+	canceled atomic.Bool // ada fetch yang ctx-nya dibatalkan
+}
+
+func (s *countingService) FetchStations(ctx context.Context) ([]StationIn, error) {
+	s.mu.Lock()
+	s.calls++
+	call := s.calls
+	s.mu.Unlock()
+
+	if s.gate != nil {
+		select {
+		case <-s.gate:
+		case <-ctx.Done():
+			s.canceled.Store(true)
+			return nil, ctx.Err()
+		}
+	}
+	if call <= len(s.errs) && s.errs[call-1] != nil {
+		return nil, s.errs[call-1]
+	}
+
+	return []StationIn{{ID: strconv.Itoa(call)}}, nil
+}
+
+func (s *countingService) FetchSchedules(context.Context) ([]ScheduleIn, error) { return nil, nil }
+func (s *countingService) FetchFares(context.Context) ([]FareIn, error)         { return nil, nil }
+
+func (s *countingService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestCachedServiceSequential(t *testing.T) {
+	errUpstream := errors.New("upstream down")
+
+	type step struct {
+		wait      time.Duration // jeda sebelum panggilan
+		wantID    string        // kosong = error
+		wantStale bool
+	}
+
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		stale     time.Duration
+		errs      []error
+		steps     []step
+		wantCalls int
+		wantStats CacheStats
+	}{
+		{
+			name:      "fresh value served from cache",
+			ttl:       time.Minute,
+			steps:     []step{{wantID: "1"}, {wantID: "1"}, {wantID: "1"}},
+			wantCalls: 1,
+			wantStats: CacheStats{Hits: 2, Misses: 1},
+		},
+		{
+			name:      "ttl 0 bypasses cache",
+			steps:     []step{{wantID: "1"}, {wantID: "2"}, {wantID: "3"}},
+			wantCalls: 3,
+			wantStats: CacheStats{Misses: 3},
+		},
+		{
+			name:      "ttl 0 has no stale fallback",
+			errs:      []error{nil, errUpstream},
+			steps:     []step{{wantID: "1"}, {}},
+			wantCalls: 2,
+			wantStats: CacheStats{Misses: 2, FetchErrors: 1},
+		},
+		{
+			name:      "expired value served on upstream error",
+			ttl:       50 * time.Millisecond,
+			errs:      []error{nil, errUpstream},
+			steps:     []step{{wantID: "1"}, {wait: 80 * time.Millisecond, wantID: "1", wantStale: true}},
+			wantCalls: 2,
+			wantStats: CacheStats{Misses: 2, StaleServed: 1, FetchErrors: 1},
+		},
+		{
+			name:      "error without cached value",
+			ttl:       time.Minute,
+			errs:      []error{errUpstream},
+			steps:     []step{{}},
+			wantCalls: 1,
+			wantStats: CacheStats{Misses: 1, FetchErrors: 1},
+		},
+		{
+			name:  "stale window serves old value while refreshing",
+			ttl:   100 * time.Millisecond,
+			stale: time.Minute,
+			steps: []step{
+				{wantID: "1"},
+				{wait: 150 * time.Millisecond, wantID: "1", wantStale: true},
+				{wait: 30 * time.Millisecond, wantID: "2"},
+			},
+			wantCalls: 2,
+			wantStats: CacheStats{Hits: 1, Misses: 1, StaleServed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &countingService{errs: tt.errs}
+			cached := NewCachedService(svc, CacheTTL{Stations: tt.ttl, StaleWindow: tt.stale})
+
+			for i, st := range tt.steps {
+				time.Sleep(st.wait)
+
+				ctx := WithSnapshotUsage(context.Background())
+				stations, err := cached.FetchStations(ctx)
+				if st.wantID == "" {
+					if err == nil {
+						t.Fatalf("step %d: FetchStations() = %+v, want error", i, stations)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d: FetchStations() error = %v", i, err)
+				}
+				if len(stations) != 1 || stations[0].ID != st.wantID {
+					t.Fatalf("step %d: FetchStations() = %+v, want fetch %s", i, stations, st.wantID)
+				}
+				if info, _ := SnapshotUsed(ctx); info.Stale != st.wantStale {
+					t.Errorf("step %d: stale = %v, want %v", i, info.Stale, st.wantStale)
+				}
+			}
+
+			if got := svc.callCount(); got != tt.wantCalls {
+				t.Errorf("upstream calls = %d, want %d", got, tt.wantCalls)
+			}
+			if got := cached.Stats()["stations"]; got != tt.wantStats {
+				t.Errorf("stats = %+v, want %+v", got, tt.wantStats)
+			}
+		})
+	}
+}
+
+// waitFor menunggu sampai cond bernilai true, gagal kalau lewat satu detik.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachedServiceCoalescesConcurrentMisses(t *testing.T) {
+	const callers = 8
+
+	svc := &countingService{gate: make(chan struct{})}
+	cached := NewCachedService(svc, CacheTTL{Stations: time.Minute})
+
+	var wg sync.WaitGroup
+	results := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stations, err := cached.FetchStations(context.Background())
+			if err != nil || len(stations) != 1 {
+				results <- "error"
+				return
+			}
+			results <- stations[0].ID
+		}()
+	}
+
+	// Semua pemanggil harus sudah menunggu fetch yang sama sebelum upstream menjawab
+	waitFor(t, func() bool {
+		stats := cached.Stats()["stations"]
+		return stats.Misses+stats.Coalesced == callers
+	})
+	close(svc.gate)
+	wg.Wait()
+	close(results)
+
+	for id := range results {
+		if id != "1" {
+			t.Errorf("caller got %q, want result of fetch 1", id)
+		}
+	}
+	if got := svc.callCount(); got != 1 {
+		t.Errorf("upstream calls = %d, want 1", got)
+	}
+	if stats := cached.Stats()["stations"]; stats.Misses != 1 || stats.Coalesced != callers-1 {
+		t.Errorf("stats = %+v, want 1 miss and %d coalesced", stats, callers-1)
+	}
+}
+
+func TestCachedServiceWaiterCancel(t *testing.T) {
+	tests := []struct {
+		name         string
+		cancelAll    bool
+		wantCanceled bool
+	}{
+		{name: "fetch continues while another caller waits", cancelAll: false, wantCanceled: false},
+		{name: "fetch canceled after last caller leaves", cancelAll: true, wantCanceled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &countingService{gate: make(chan struct{})}
+			cached := NewCachedService(svc, CacheTTL{Stations: time.Minute})
+
+			leaving, leave := context.WithCancel(context.Background())
+			staying, stay := context.WithCancel(context.Background())
+			defer stay()
+
+			errs := make(chan error, 2)
+			for _, ctx := range []context.Context{leaving, staying} {
+				go func(ctx context.Context) {
+					_, err := cached.FetchStations(ctx)
+					errs <- err
+				}(ctx)
+			}
+			waitFor(t, func() bool {
+				stats := cached.Stats()["stations"]
+				return stats.Misses+stats.Coalesced == 2
+			})
+
+			leave()
+			if err := <-errs; !errors.Is(err, context.Canceled) {
+				t.Fatalf("canceled caller error = %v, want context.Canceled", err)
+			}
+
+			if tt.cancelAll {
+				stay()
+				waitFor(t, svc.canceled.Load)
+			} else {
+				close(svc.gate)
+			}
+			err := <-errs
+
+			if got := svc.canceled.Load(); got != tt.wantCanceled {
+				t.Errorf("upstream fetch canceled = %v, want %v", got, tt.wantCanceled)
+			}
+			if !tt.cancelAll && err != nil {
+				t.Errorf("waiting caller error = %v, want nil", err)
+			}
+			if got := svc.callCount(); got != 1 {
+				t.Errorf("upstream calls = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package station
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/timetable"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// Anomaly adalah satu masalah kualitas data pada record upstream.
+// Dropped bernilai true kalau record (atau entri jadwal) tersebut tidak ikut disajikan (misalnya nid kosong).
+type Anomaly struct {
+	StationID string `json:"-"`
+	Station   string `json:"-"`
+	Resource  string `json:"resource"`
+	Field     string `json:"field"`
+	Value     string `json:"value"`
+	Problem   string `json:"problem"`
+	Dropped   bool   `json:"dropped,omitempty"`
+}
+
+// ResourceQuality adalah hasil validasi payload terakhir untuk satu resource.
+// - Source   → "upstream" atau "snapshot".
+// - Rejected → payload upstream ditolak karena skemanya tidak cocok (Error berisi alasannya),
+// data sebelumnya (cache / snapshot) tetap dipakai.
+// - Error    → juga diisi kalau upstream gagal dan snapshot dipakai sebagai cadangan.
+type ResourceQuality struct {
+	Resource  string    `json:"resource"`
+	Source    string    `json:"source"`
+	CheckedAt time.Time `json:"checked_at"`
+	Records   int       `json:"records"`
+	Served    int       `json:"served"`
+	Anomalies int       `json:"anomalies"`
+	Rejected  bool      `json:"rejected"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// StationQuality mengelompokkan anomali per stasiun.
+type StationQuality struct {
+	ID        string    `json:"id"`
+	Nama      string    `json:"nama"`
+	Anomalies []Anomaly `json:"anomalies"`
+}
+
+// QualityReport adalah laporan kualitas data upstream untuk endpoint admin.
+type QualityReport struct {
+	Healthy        bool              `json:"healthy"`
+	TotalAnomalies int               `json:"total_anomalies"`
+	Resources      []ResourceQuality `json:"resources"`
+	Stations       []StationQuality  `json:"stations"`
+}
+
+// QualityReporter diimplementasikan oleh Service yang memvalidasi payload upstream.
+type QualityReporter interface {
+	DataQuality() QualityReport
+}
+
+// qualityTracker menyimpan hasil validasi terakhir setiap resource.
+type qualityTracker struct {
+	mu        sync.Mutex
+	resources map[string]ResourceQuality
+	anomalies map[string][]Anomaly
+}
+
+func (t *qualityTracker) record(result ResourceQuality, anomalies []Anomaly) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.resources == nil {
+		t.resources = map[string]ResourceQuality{}
+		t.anomalies = map[string][]Anomaly{}
+	}
+	t.resources[result.Resource] = result
+	// Payload yang ditolak tidak disajikan, jadi anomali data yang masih dipakai tetap ditampilkan
+	if !result.Rejected {
+		t.anomalies[result.Resource] = anomalies
+	}
+}
+
+// fallback menandai bahwa resource sedang disajikan dari snapshot karena upstream gagal dengan err.
+func (t *qualityTracker) fallback(resource string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := t.resources[resource]
+	result.Error = err.Error()
+	if appErr, ok := apperror.As(err); ok && appErr.Code == apperror.CodeUpstreamSchema {
+		result.Rejected = true
+	}
+	t.resources[resource] = result
+}
+
+func (t *qualityTracker) report() QualityReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := QualityReport{Healthy: true, Resources: []ResourceQuality{}, Stations: []StationQuality{}}
+	byStation := map[string]*StationQuality{}
+	for _, resource := range []string{ResourceStations, ResourceSchedules, ResourceFares} {
+		result, ok := t.resources[resource]
+		if !ok {
+			continue
+		}
+		report.Resources = append(report.Resources, result)
+		if result.Rejected {
+			report.Healthy = false
+		}
+
+		for _, a := range t.anomalies[resource] {
+			st, ok := byStation[a.StationID]
+			if !ok {
+				st = &StationQuality{ID: a.StationID, Nama: a.Station}
+				byStation[a.StationID] = st
+			}
+			st.Anomalies = append(st.Anomalies, a)
+			report.TotalAnomalies++
+		}
+	}
+	if report.TotalAnomalies > 0 {
+		report.Healthy = false
+	}
+
+	for _, st := range byStation {
+		report.Stations = append(report.Stations, *st)
+	}
+	sort.Slice(report.Stations, func(i, j int) bool {
+		return report.Stations[i].ID < report.Stations[j].ID
+	})
+
+	return report
+}
+
+// validatePayload memeriksa record hasil decode untuk resource:
+//  1. Payload kosong, atau field wajib kosong di semua record (biasanya nama field upstream berubah)
+//     → payload ditolak dengan error KindUpstreamMalformed.
+//  2. Record tanpa nid atau dengan nid ganda tidak ikut disajikan.
+//  3. Masalah lain (jam, tarif, waktu tempuh, stasiun tujuan yang tidak ada) hanya dilaporkan.
+func validatePayload(resource string, items []rawStation) ([]rawStation, []Anomaly, error) {
+	if err := schemaError(resource, items); err != nil {
+		return nil, nil, err
+	}
+
+	ids := make(map[string]bool, len(items))
+	for _, item := range items {
+		ids[item.ID] = true
+	}
+
+	var (
+		valid     = make([]rawStation, 0, len(items))
+		anomalies []Anomaly
+		seen      = make(map[string]bool, len(items))
+	)
+	for _, item := range items {
+		report := func(field, value, problem string, dropped bool) {
+			anomalies = append(anomalies, Anomaly{
+				StationID: item.ID,
+				Station:   item.Title,
+				Resource:  resource,
+				Field:     field,
+				Value:     value,
+				Problem:   problem,
+				Dropped:   dropped,
+			})
+		}
+
+		switch {
+		case strings.TrimSpace(item.ID) == "":
+			report("nid", item.ID, "missing", true)
+			continue
+		case seen[item.ID]:
+			report("nid", item.ID, "duplicate", true)
+			continue
+		}
+		seen[item.ID] = true
+
+		if strings.TrimSpace(item.Title) == "" {
+			report("title", item.Title, "missing", false)
+		}
+
+		switch resource {
+		case ResourceSchedules:
+			checkTimetable(item.JadwalHIBiasa, item.JadwalLBBiasa, "jadwal_hi_biasa", report)
+			checkTimetable(item.JadwalHILibur, item.JadwalLBLibur, "jadwal_hi_libur", report)
+			checkTimetable(item.JadwalLBBiasa, item.JadwalHIBiasa, "jadwal_lb_biasa", report)
+			checkTimetable(item.JadwalLBLibur, item.JadwalHILibur, "jadwal_lb_libur", report)
+		case ResourceFares:
+			checkEstimasi(item, ids, report)
+		}
+
+		valid = append(valid, item)
+	}
+
+	return valid, anomalies, nil
+}
+
+// schemaError mendeteksi payload yang strukturnya tidak lagi cocok dengan model rawStation.
+func schemaError(resource string, items []rawStation) error {
+	var reason string
+	switch {
+	case len(items) == 0:
+		reason = "payload contains no stations"
+	case none(items, func(r rawStation) bool { return r.ID != "" }):
+		reason = "field nid is empty in every record"
+	case none(items, func(r rawStation) bool { return r.Title != "" }):
+		reason = "field title is empty in every record"
+	case resource == ResourceSchedules && none(items, func(r rawStation) bool {
+		return r.JadwalHIBiasa != "" || r.JadwalHILibur != "" || r.JadwalLBBiasa != "" || r.JadwalLBLibur != ""
+	}):
+		reason = "schedule fields are empty in every record"
+	case resource == ResourceFares && none(items, func(r rawStation) bool { return len(r.Estimasi) > 0 }):
+		reason = "field estimasi is empty in every record"
+	default:
+		return nil
+	}
+
+	return apperror.New(apperror.KindUpstreamMalformed, apperror.CodeUpstreamSchema, "upstream "+resource+" payload failed schema validation: "+reason)
+}
+
+func none(items []rawStation, fn func(rawStation) bool) bool {
+	for _, item := range items {
+		if fn(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkTimetable memeriksa string jadwal mentah ("05:00:00,05:10:00,...") dengan parser yang sama
+// dengan usecase (timetable.Parse), jadi entri yang dilaporkan di sini adalah entri yang dilewati saat disajikan.
+// Jadwal kosong hanya dilaporkan kalau arah sebaliknya (opposite, jenis hari yang sama) juga kosong:
+// di stasiun ujung (Lebak Bulus, Bundaran HI) memang tidak ada kereta ke arah stasiun itu sendiri.
+func checkTimetable(raw, opposite, field string, report func(field, value, problem string, dropped bool)) {
+	if strings.TrimSpace(raw) == "" {
+		if strings.TrimSpace(opposite) == "" {
+			report(field, raw, "missing", false)
+		}
+		return
+	}
+
+	offsets, issues := timetable.Parse(raw)
+	for _, issue := range issues {
+		report(fmt.Sprintf("%s[%d]", field, issue.Index), issue.Value, issue.Reason, true)
+	}
+	if len(offsets) == 0 {
+		report(field, raw, "no valid departure times", false)
+	}
+}
+
+// checkEstimasi memeriksa daftar estimasi tarif milik satu stasiun.
+// Format angka mengikuti aturan ParseRupiah / ParseMinutes di usecase.
+func checkEstimasi(item rawStation, ids map[string]bool, report func(field, value, problem string, dropped bool)) {
+	if len(item.Estimasi) == 0 {
+		report("estimasi", "", "missing", false)
+		return
+	}
+
+	for i, e := range item.Estimasi {
+		prefix := fmt.Sprintf("estimasi[%d].", i)
+		switch {
+		case e.IDStasiunTujuan == "":
+			report(prefix+"stasiun_nid", e.IDStasiunTujuan, "missing", false)
+		case !ids[e.IDStasiunTujuan]:
+			report(prefix+"stasiun_nid", e.IDStasiunTujuan, "unknown station", false)
+		}
+		if !isRupiah(e.Tarif) {
+			report(prefix+"tarif", e.Tarif, "not a rupiah amount", false)
+		}
+		if !isMinutes(e.Waktu) {
+			report(prefix+"waktu", e.Waktu, "not a number of minutes", false)
+		}
+	}
+}
+
+func isRupiah(raw string) bool {
+	cleaned := strings.TrimSpace(raw)
+	cleaned = strings.TrimPrefix(strings.TrimPrefix(cleaned, "Rp"), "IDR")
+	cleaned = strings.NewReplacer(".", "", ",", "", " ", "").Replace(cleaned)
+
+	amount, err := strconv.ParseInt(cleaned, 10, 64)
+	return err == nil && amount >= 0
+}
+
+func isMinutes(raw string) bool {
+	minutes, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw), "menit")))
+	return err == nil && minutes >= 0
+}
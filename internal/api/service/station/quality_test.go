@@ -0,0 +1,148 @@
+package station
+
+import (
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+func TestValidatePayloadSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		items    []rawStation
+		wantErr  bool
+	}{
+		{name: "empty payload", resource: ResourceStations, wantErr: true},
+		{name: "nid renamed", resource: ResourceStations, items: []rawStation{{Title: "A"}, {Title: "B"}}, wantErr: true},
+		{name: "title renamed", resource: ResourceStations, items: []rawStation{{ID: "1"}, {ID: "2"}}, wantErr: true},
+		{name: "schedule fields renamed", resource: ResourceSchedules, items: []rawStation{{ID: "1", Title: "A"}}, wantErr: true},
+		{name: "estimasi renamed", resource: ResourceFares, items: []rawStation{{ID: "1", Title: "A"}}, wantErr: true},
+		{name: "stations do not need schedules", resource: ResourceStations, items: []rawStation{{ID: "1", Title: "A"}}},
+		{name: "one record with schedule is enough", resource: ResourceSchedules, items: []rawStation{{ID: "1", Title: "A"}, {ID: "2", Title: "B", JadwalHIBiasa: "05:00"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := validatePayload(tt.resource, tt.items)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("validatePayload() error = %v", err)
+				}
+				return
+			}
+
+			appErr, ok := apperror.As(err)
+			if !ok || appErr.Code != apperror.CodeUpstreamSchema || appErr.Kind != apperror.KindUpstreamMalformed {
+				t.Fatalf("validatePayload() error = %v, want %s", err, apperror.CodeUpstreamSchema)
+			}
+		})
+	}
+}
+
+func TestValidatePayloadAnomalies(t *testing.T) {
+	// Lebak Bulus (38) dan Bundaran HI (21) adalah stasiun ujung, masing-masing hanya punya satu arah
+	terminusLB := rawStation{ID: "38", Title: "Lebak Bulus", JadwalHIBiasa: "05:00,05:10", JadwalHILibur: "06:00"}
+	terminusHI := rawStation{ID: "21", Title: "Bundaran HI", JadwalLBBiasa: "05:00,05:10", JadwalLBLibur: "06:00"}
+	middle := rawStation{ID: "30", Title: "Istora", JadwalHIBiasa: "05:20", JadwalHILibur: "06:20", JadwalLBBiasa: "05:30", JadwalLBLibur: "06:30"}
+
+	type anomaly struct {
+		station, field, problem string
+		dropped                 bool
+	}
+
+	tests := []struct {
+		name      string
+		resource  string
+		items     []rawStation
+		wantIDs   []string
+		anomalies []anomaly
+	}{
+		{
+			name:     "clean schedules with one-way termini",
+			resource: ResourceSchedules,
+			items:    []rawStation{terminusLB, middle, terminusHI},
+			wantIDs:  []string{"38", "30", "21"},
+		},
+		{
+			name:     "empty in both directions is missing",
+			resource: ResourceSchedules,
+			items:    []rawStation{middle, {ID: "31", Title: "Senayan", JadwalHIBiasa: "05:00", JadwalLBBiasa: "05:10"}},
+			wantIDs:  []string{"30", "31"},
+			anomalies: []anomaly{
+				{station: "31", field: "jadwal_hi_libur", problem: "missing"},
+				{station: "31", field: "jadwal_lb_libur", problem: "missing"},
+			},
+		},
+		{
+			name:     "missing and duplicate nid dropped",
+			resource: ResourceSchedules,
+			items:    []rawStation{middle, {Title: "Tanpa ID", JadwalHIBiasa: "05:00"}, middle},
+			wantIDs:  []string{"30"},
+			anomalies: []anomaly{
+				{station: "", field: "nid", problem: "missing", dropped: true},
+				{station: "30", field: "nid", problem: "duplicate", dropped: true},
+			},
+		},
+		{
+			name:     "broken timetable entries reported",
+			resource: ResourceSchedules,
+			items: []rawStation{
+				{ID: "30", Title: "Istora", JadwalHIBiasa: "05:20,5.30", JadwalHILibur: "x", JadwalLBBiasa: "05:30", JadwalLBLibur: "06:30"},
+			},
+			wantIDs: []string{"30"},
+			anomalies: []anomaly{
+				{station: "30", field: "jadwal_hi_biasa[1]", dropped: true},
+				{station: "30", field: "jadwal_hi_libur[0]", dropped: true},
+				{station: "30", field: "jadwal_hi_libur", problem: "no valid departure times"},
+			},
+		},
+		{
+			name:     "fare problems reported but kept",
+			resource: ResourceFares,
+			items: []rawStation{
+				{ID: "38", Title: "Lebak Bulus", Estimasi: []EstimasiIn{
+					{IDStasiunTujuan: "21", Tarif: "Rp 14.000", Waktu: "30 menit"},
+					{IDStasiunTujuan: "99", Tarif: "gratis", Waktu: "lama"},
+				}},
+				{ID: "21", Title: "Bundaran HI"},
+			},
+			wantIDs: []string{"38", "21"},
+			anomalies: []anomaly{
+				{station: "38", field: "estimasi[1].stasiun_nid", problem: "unknown station"},
+				{station: "38", field: "estimasi[1].tarif", problem: "not a rupiah amount"},
+				{station: "38", field: "estimasi[1].waktu", problem: "not a number of minutes"},
+				{station: "21", field: "estimasi", problem: "missing"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, anomalies, err := validatePayload(tt.resource, tt.items)
+			if err != nil {
+				t.Fatalf("validatePayload() error = %v", err)
+			}
+
+			if len(valid) != len(tt.wantIDs) {
+				t.Fatalf("valid = %d records, want %v", len(valid), tt.wantIDs)
+			}
+			for i, item := range valid {
+				if item.ID != tt.wantIDs[i] {
+					t.Errorf("valid[%d].ID = %q, want %q", i, item.ID, tt.wantIDs[i])
+				}
+			}
+
+			if len(anomalies) != len(tt.anomalies) {
+				t.Fatalf("anomalies = %+v, want %+v", anomalies, tt.anomalies)
+			}
+			for i, a := range anomalies {
+				want := tt.anomalies[i]
+				if a.StationID != want.station || a.Field != want.field || a.Dropped != want.dropped || a.Resource != tt.resource {
+					t.Errorf("anomaly %d = %+v, want %+v", i, a, want)
+				}
+				if want.problem != "" && a.Problem != want.problem {
+					t.Errorf("anomaly %d problem = %q, want %q", i, a.Problem, want.problem)
+				}
+			}
+		})
+	}
+}
@@ -1,87 +1,364 @@
 package station
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
 	"github.com/IkrmMrbsy/mrt-schedules/pkg/client"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/snapshot"
+)
+
+// Nama resource, dipakai sebagai key snapshot di disk.
+const (
+	ResourceStations  = "stations"
+	ResourceSchedules = "schedules"
+	ResourceFares     = "fares"
 )
 
 // Service adalah "kontrak" (interface) yang menentukan fungsi apa saja
 // yang harus dimiliki oleh service station.
-// Di sini cuma ada 1 fungsi: GetAllStation.
 type Service interface {
-	FetchStations() ([]StationIn, error)
-	FetchSchedules() ([]ScheduleIn, error)
-	FetchFares() ([]FareIn, error)
+	FetchStations(ctx context.Context) ([]StationIn, error)
+	FetchSchedules(ctx context.Context) ([]ScheduleIn, error)
+	FetchFares(ctx context.Context) ([]FareIn, error)
 }
 
 // service adalah implementasi dari Service.
 // Struct ini punya field "client" untuk melakukan HTTP request.
+// Setiap resource punya URL sumber sendiri (default apiURL); resource yang berbagi URL
+// juga berbagi satu source, jadi payload-nya cukup diunduh dan di-decode sekali.
+// Kalau store di-set, setiap payload yang berhasil di-decode dan lolos validasi skema juga disimpan ke disk.
+// Hasil validasi terakhir tiap resource disimpan di quality (lihat DataQuality).
 type service struct {
-	client *http.Client
-	apiURL string
+	client  *client.Client
+	apiURL  string
+	urls    map[string]string
+	sources map[string]*source
+	reuse   time.Duration
+	store   *snapshot.Store
+	offline bool
+	quality *qualityTracker
+}
+
+// Option dipakai untuk mengatur perilaku tambahan service saat dibuat.
+type Option func(*service)
+
+// WithClient mengganti HTTP client upstream, misalnya untuk memakai retry policy tertentu
+// atau membagikan statistik client ke endpoint admin.
+func WithClient(c *client.Client) Option {
+	return func(s *service) {
+		s.client = c
+	}
+}
+
+// WithResourceURL mengatur URL upstream untuk satu resource (ResourceStations, ResourceSchedules, ResourceFares).
+// URL kosong diabaikan, jadi resource tersebut tetap memakai apiURL.
+func WithResourceURL(resource, url string) Option {
+	return func(s *service) {
+		if url != "" {
+			s.urls[resource] = url
+		}
+	}
+}
+
+// WithReuseWindow mengatur berapa lama payload upstream dipakai ulang oleh resource lain
+// yang berbagi URL yang sama (default DefaultReuseWindow, 0 = selalu unduh ulang).
+func WithReuseWindow(window time.Duration) Option {
+	return func(s *service) {
+		s.reuse = window
+	}
+}
+
+// WithSnapshotStore mengaktifkan penyimpanan snapshot ke disk.
+// Snapshot juga dipakai sebagai cadangan kalau upstream tidak bisa dihubungi.
+func WithSnapshotStore(store *snapshot.Store) Option {
+	return func(s *service) {
+		s.store = store
+	}
+}
+
+// WithOfflineMode membuat service hanya membaca dari snapshot, tanpa memanggil upstream.
+func WithOfflineMode(offline bool) Option {
+	return func(s *service) {
+		s.offline = offline
+	}
 }
 
 // NewService membuat object service baru.
 // Di sini kita juga set timeout untuk HTTP client supaya request tidak menggantung terlalu lama.
-func NewService(timeout time.Duration, apiURL string) Service {
-	return &service{
-		client: &http.Client{
+// Tanpa WithClient, client memakai client.DefaultRetryPolicy.
+func NewService(timeout time.Duration, apiURL string, opts ...Option) Service {
+	s := &service{
+		client: client.NewClient(&http.Client{
 			Timeout: timeout,
-		},
-		apiURL: apiURL,
+		}, client.DefaultRetryPolicy()),
+		apiURL:  apiURL,
+		urls:    map[string]string{},
+		sources: map[string]*source{},
+		reuse:   DefaultReuseWindow,
+		quality: &qualityTracker{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	// Resource dengan URL yang sama memakai source yang sama
+	for _, resource := range []string{ResourceStations, ResourceSchedules, ResourceFares} {
+		url := s.resourceURL(resource)
+		if _, ok := s.sources[url]; !ok {
+			s.sources[url] = &source{url: url}
+		}
+	}
+
+	return s
+}
+
+// datedService diimplementasikan Service yang juga bisa melaporkan kapan data yang dikembalikan
+// diambil dari upstream. Untuk data dari snapshot di disk, FetchedAt adalah waktu snapshot itu dibuat
+// dan Stale bernilai true, jadi response ikut ditandai sebagai data lama. Umur cache tetap dihitung
+// dari waktu data dimuat, supaya snapshot lama tidak dibaca ulang dari disk di setiap request.
+type datedService interface {
+	fetchStations(ctx context.Context) ([]StationIn, SnapshotInfo, error)
+	fetchSchedules(ctx context.Context) ([]ScheduleIn, SnapshotInfo, error)
+	fetchFares(ctx context.Context) ([]FareIn, SnapshotInfo, error)
 }
 
-// GetAllStation memanggil API MRT (https://jakartamrt.co.id/id/val/stasiuns)
+// FetchStations memanggil API MRT (https://jakartamrt.co.id/id/val/stasiuns)
 // untuk mengambil daftar stasiun.
-// 1. Panggil API → dapat response dalam bentuk byte.
-// 2. Ubah byte jadi slice of StationIn (pakai json.Unmarshal).
-// 3. Konversi StationIn → StationOut.
-// 4. Kembalikan hasilnya ke pemanggil.
-func (s *service) FetchStations() ([]StationIn, error) {
-	// Lakukan HTTP GET ke API
-	byteResponse, err := client.DoRequest(s.client, s.apiURL)
+// 1. Ambil payload mentah dari source milik resource stations.
+// 2. Ubah setiap item mentah jadi StationIn.
+// 3. Kembalikan hasilnya ke pemanggil.
+func (s *service) FetchStations(ctx context.Context) ([]StationIn, error) {
+	stations, _, err := s.fetchStations(ctx)
+	return stations, err
+}
+
+func (s *service) FetchSchedules(ctx context.Context) ([]ScheduleIn, error) {
+	schedules, _, err := s.fetchSchedules(ctx)
+	return schedules, err
+}
+
+func (s *service) FetchFares(ctx context.Context) ([]FareIn, error) {
+	fares, _, err := s.fetchFares(ctx)
+	return fares, err
+}
+
+func (s *service) fetchStations(ctx context.Context) ([]StationIn, SnapshotInfo, error) {
+	items, info, err := s.fetch(ctx, ResourceStations)
 	if err != nil {
-		return nil, err
+		return nil, SnapshotInfo{}, err
 	}
 
-	// Simpan hasil parsing dari API ke slice of StationIn
-	var stations []StationIn
-	err = json.Unmarshal(byteResponse, &stations)
+	// Simpan hasil konversi ke slice of StationIn
+	stations := make([]StationIn, 0, len(items))
+	for _, item := range items {
+		stations = append(stations, item.station())
+	}
+
+	return stations, info, nil
+}
+
+func (s *service) fetchSchedules(ctx context.Context) ([]ScheduleIn, SnapshotInfo, error) {
+	items, info, err := s.fetch(ctx, ResourceSchedules)
 	if err != nil {
-		return nil, err
+		return nil, SnapshotInfo{}, err
+	}
+
+	schedules := make([]ScheduleIn, 0, len(items))
+	for _, item := range items {
+		schedules = append(schedules, item.schedule())
 	}
 
-	return stations, nil
+	return schedules, info, nil
 }
 
-func (s *service) FetchSchedules() ([]ScheduleIn, error) {
-	byteResponse, err := client.DoRequest(s.client, s.apiURL)
+func (s *service) fetchFares(ctx context.Context) ([]FareIn, SnapshotInfo, error) {
+	items, info, err := s.fetch(ctx, ResourceFares)
 	if err != nil {
-		return nil, err
+		return nil, SnapshotInfo{}, err
 	}
 
-	var schedules []ScheduleIn
-	if err := json.Unmarshal(byteResponse, &schedules); err != nil {
-		return nil, err
+	fares := make([]FareIn, 0, len(items))
+	for _, item := range items {
+		fares = append(fares, item.fare())
 	}
 
-	return schedules, nil
+	return fares, info, nil
 }
 
-func (s *service) FetchFares() ([]FareIn, error) {
-	byteResp, err := client.DoRequest(s.client, s.apiURL)
+// resourceURL mengembalikan URL upstream untuk resource, fallback ke apiURL.
+func (s *service) resourceURL(resource string) string {
+	if url, ok := s.urls[resource]; ok {
+		return url
+	}
+
+	return s.apiURL
+}
+
+// fetch mengambil payload mentah untuk resource beserta waktu payload itu diambil dari upstream.
+//  1. Mode offline → langsung baca snapshot dari disk.
+//  2. Upstream sukses → decode sekali, lalu simpan payload sebagai snapshot terbaru
+//     untuk semua resource yang berbagi URL yang sama.
+//  3. Upstream gagal → coba pakai snapshot terakhir di disk, kalau tidak ada kembalikan error aslinya.
+//  4. ctx dibatalkan / deadline lewat → langsung kembalikan ctx.Err(), tanpa fallback snapshot.
+//
+// Untuk data dari snapshot, FetchedAt adalah waktu snapshot itu dibuat (bukan sekarang) dan Stale bernilai true.
+// Payload upstream divalidasi sekali per download (lihat process), snapshot divalidasi setiap dibaca;
+// payload yang skemanya tidak cocok diperlakukan sama seperti upstream gagal.
+func (s *service) fetch(ctx context.Context, resource string) ([]rawStation, SnapshotInfo, error) {
+	if s.offline {
+		items, fetchedAt, err := s.loadSnapshot(resource)
+		if err != nil {
+			return nil, SnapshotInfo{}, &apperror.Error{Kind: apperror.KindUpstreamUnavailable, Code: apperror.CodeSnapshotUnavailable, Message: "offline snapshot for " + resource + " is unavailable", Err: err}
+		}
+		items, err = s.validate(resource, "snapshot", items)
+		return items, SnapshotInfo{FetchedAt: fetchedAt, Stale: true}, err
+	}
+
+	// Lakukan HTTP GET ke API (atau pakai ulang payload yang baru saja diambil)
+	url := s.resourceURL(resource)
+	checked, fetchedAt, err := s.sources[url].get(ctx, s.reuse, s.download, func(items []rawStation, payload []byte, fetchedAt time.Time) map[string]checkedPayload {
+		return s.process(url, items, payload, fetchedAt)
+	})
+	if err == nil {
+		if err = checked[resource].err; err == nil {
+			return checked[resource].items, SnapshotInfo{FetchedAt: fetchedAt}, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, SnapshotInfo{}, ctx.Err()
+	}
+
+	if s.store != nil {
+		if items, fetchedAt, snapErr := s.loadSnapshot(resource); snapErr == nil {
+			if items, snapErr = s.validate(resource, "snapshot", items); snapErr == nil {
+				s.quality.fallback(resource, err)
+				log.Printf("service: upstream %s failed (%v), using disk snapshot from %s", resource, err, fetchedAt.Format(time.RFC3339))
+				return items, SnapshotInfo{FetchedAt: fetchedAt, Stale: true}, nil
+			}
+		}
+	}
+
+	return nil, SnapshotInfo{}, upstreamError(resource, err)
+}
+
+// validate menjalankan validatePayload, mencatat hasilnya untuk laporan data-quality,
+// lalu mengembalikan record yang layak disajikan.
+func (s *service) validate(resource, origin string, items []rawStation) ([]rawStation, error) {
+	valid, anomalies, err := validatePayload(resource, items)
+
+	result := ResourceQuality{
+		Resource:  resource,
+		Source:    origin,
+		CheckedAt: time.Now(),
+		Records:   len(items),
+		Served:    len(valid),
+		Anomalies: len(anomalies),
+	}
+	if err != nil {
+		result.Rejected = true
+		result.Error = err.Error()
+		log.Printf("service: %s %s rejected: %v", origin, resource, err)
+	} else if len(anomalies) > 0 {
+		log.Printf("service: %s %s has %d data-quality anomalies", origin, resource, len(anomalies))
+	}
+	s.quality.record(result, anomalies)
+
+	return valid, err
+}
+
+// DataQuality mengembalikan hasil validasi payload terakhir untuk semua resource.
+func (s *service) DataQuality() QualityReport {
+	return s.quality.report()
+}
+
+// upstreamError menggolongkan error dari upstream menjadi error domain:
+// - timeout                          → KindUpstreamTimeout (504)
+// - payload bukan JSON yang valid    → KindUpstreamMalformed (502)
+// - status 4xx yang tidak di-retry   → KindUpstreamMalformed (502), upstream membalas tapi tidak bisa dipakai
+// - circuit breaker, koneksi, 5xx    → KindUpstreamUnavailable (503)
+// Pesan untuk client tidak menyertakan URL upstream; error aslinya tetap ada di Err.
+// Error yang sudah berupa apperror (misalnya payload ditolak validasi skema) dikembalikan apa adanya.
+func upstreamError(resource string, err error) error {
+	var (
+		netErr    net.Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		statusErr *client.StatusError
+	)
+
+	if _, ok := apperror.As(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return err
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &apperror.Error{Kind: apperror.KindUpstreamTimeout, Code: apperror.CodeUpstreamTimeout, Message: "upstream " + resource + " request timed out", Err: err}
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return &apperror.Error{Kind: apperror.KindUpstreamMalformed, Code: apperror.CodeUpstreamMalformed, Message: "upstream " + resource + " payload is malformed", Err: err}
+	case errors.Is(err, client.ErrCircuitOpen):
+		return &apperror.Error{Kind: apperror.KindUpstreamUnavailable, Code: apperror.CodeUpstreamCircuitOpen, Message: "upstream " + resource + " is temporarily unavailable", Err: err}
+	case errors.As(err, &statusErr) && statusErr.StatusCode < 500 && statusErr.StatusCode != 429:
+		return &apperror.Error{Kind: apperror.KindUpstreamMalformed, Code: apperror.CodeUpstreamBadResponse, Message: "upstream " + resource + " responded with " + statusErr.Status, Err: err}
+	default:
+		return &apperror.Error{Kind: apperror.KindUpstreamUnavailable, Code: apperror.CodeUpstreamUnavailable, Message: "upstream " + resource + " is unavailable", Err: err}
+	}
+}
+
+func (s *service) download(ctx context.Context, url string) ([]byte, error) {
+	return s.client.Get(ctx, url)
+}
+
+// process dipanggil sekali untuk setiap payload upstream yang berhasil di-decode dari url:
+// payload divalidasi untuk setiap resource yang bersumber dari url, lalu disimpan sebagai snapshot resource tersebut.
+// Payload yang ditolak untuk suatu resource tidak disimpan, supaya snapshot lama yang masih bagus tidak tertimpa.
+func (s *service) process(url string, items []rawStation, payload []byte, fetchedAt time.Time) map[string]checkedPayload {
+	checked := map[string]checkedPayload{}
+	for _, resource := range []string{ResourceStations, ResourceSchedules, ResourceFares} {
+		if s.resourceURL(resource) != url {
+			continue
+		}
+
+		valid, err := s.validate(resource, "upstream", items)
+		checked[resource] = checkedPayload{items: valid, err: err}
+		if s.store == nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("service: not saving %s snapshot: %v", resource, err)
+			continue
+		}
+		if err := s.store.Save(resource, payload, fetchedAt); err != nil {
+			log.Printf("service: failed to save %s snapshot: %v", resource, err)
+		}
+	}
+
+	return checked
+}
+
+// loadSnapshot membaca snapshot resource dari disk beserta waktu payload-nya diambil dari upstream.
+func (s *service) loadSnapshot(resource string) ([]rawStation, time.Time, error) {
+	if s.store == nil {
+		return nil, time.Time{}, errors.New("offline mode requires a snapshot store")
+	}
+
+	record, err := s.store.Load(resource)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
-	var stations []FareIn
-	if err := json.Unmarshal(byteResp, &stations); err != nil {
-		return nil, err
+	var items []rawStation
+	if err := json.Unmarshal(record.Payload, &items); err != nil {
+		return nil, time.Time{}, err
 	}
 
-	return stations, nil
+	return items, record.FetchedAt, nil
 }
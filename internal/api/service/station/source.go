@@ -0,0 +1,179 @@
+package station
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultReuseWindow adalah lama payload upstream dipakai ulang oleh resource lain
+// yang berbagi URL yang sama, supaya satu siklus refresh tidak mengunduh payload yang sama berkali-kali.
+const DefaultReuseWindow = 30 * time.Second
+
+// rawStation adalah model mentah gabungan dari payload upstream.
+// Satu payload stasiun MRT berisi data stasiun, jadwal, dan tarif sekaligus,
+// jadi cukup di-decode sekali lalu dipecah ke StationIn, ScheduleIn, dan FareIn.
+type rawStation struct {
+	ID    string `json:"nid"`
+	Title string `json:"title"`
+
+	Antarmoda     string        `json:"antarmodas"`
+	PetaLokalitas string        `json:"peta_lokalitas"`
+	Banner        string        `json:"banner"`
+	Retails       []RetailIn    `json:"retails"`
+	Fasilitas     []FasilitasIn `json:"fasilitas"`
+
+	JadwalHIBiasa string `json:"jadwal_hi_biasa"`
+	JadwalHILibur string `json:"jadwal_hi_libur"`
+	JadwalLBBiasa string `json:"jadwal_lb_biasa"`
+	JadwalLBLibur string `json:"jadwal_lb_libur"`
+
+	Tarif    string       `json:"tarif"`
+	Estimasi []EstimasiIn `json:"estimasi"`
+}
+
+func (r rawStation) station() StationIn {
+	return StationIn{
+		ID:            r.ID,
+		NamaStasiun:   r.Title,
+		Antarmoda:     r.Antarmoda,
+		PetaLokalitas: r.PetaLokalitas,
+		Banner:        r.Banner,
+		Retails:       r.Retails,
+		Fasilitas:     r.Fasilitas,
+	}
+}
+
+func (r rawStation) schedule() ScheduleIn {
+	return ScheduleIn{
+		IDStasiun:             r.ID,
+		NamaStasiun:           r.Title,
+		JadwalBundaranHIBiasa: r.JadwalHIBiasa,
+		JadwalBundaranHILibur: r.JadwalHILibur,
+		JadwalLebakBulusBiasa: r.JadwalLBBiasa,
+		JadwalLebakBulusLibur: r.JadwalLBLibur,
+	}
+}
+
+func (r rawStation) fare() FareIn {
+	return FareIn{
+		ID:       r.ID,
+		Nama:     r.Title,
+		Tarif:    r.Tarif,
+		Estimasi: r.Estimasi,
+	}
+}
+
+// checkedPayload adalah record payload yang sudah divalidasi untuk satu resource.
+// err diisi kalau payload ditolak untuk resource tersebut (lihat validatePayload).
+type checkedPayload struct {
+	items []rawStation
+	err   error
+}
+
+// source adalah satu URL upstream yang bisa dipakai bersama oleh beberapa resource.
+// - Payload yang baru diambil (masih dalam reuse window) langsung dipakai ulang, beserta hasil validasinya.
+// - Pemanggil yang datang saat fetch sedang berjalan ikut menunggu hasil fetch yang sama.
+type source struct {
+	url string
+
+	mu        sync.Mutex
+	checked   map[string]checkedPayload
+	fetchedAt time.Time
+	inflight  *sourceCall
+}
+
+// sourceCall adalah satu download yang sedang berjalan. waiters hanya diubah saat source.mu terkunci.
+type sourceCall struct {
+	done      chan struct{}
+	checked   map[string]checkedPayload
+	fetchedAt time.Time
+	err       error
+
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// get mengembalikan payload terbaru dari source (per resource) beserta waktu payload itu diunduh.
+// download dipanggil paling banyak sekali untuk semua pemanggil yang bersamaan, dan process dipanggil
+// sekali untuk setiap payload yang berhasil di-decode; hasilnya dipakai semua pemanggil sampai reuse window habis.
+//
+// Download dipakai bersama, jadi tidak ikut batal kalau ctx salah satu pemanggil dibatalkan.
+// Pemanggil yang ctx-nya selesai berhenti menunggu dan mendapat ctx.Err(); kalau itu pemanggil
+// terakhir yang menunggu, download (termasuk retry-nya) dibatalkan.
+func (src *source) get(ctx context.Context, reuse time.Duration, download func(ctx context.Context, url string) ([]byte, error), process func(items []rawStation, payload []byte, fetchedAt time.Time) map[string]checkedPayload) (map[string]checkedPayload, time.Time, error) {
+	src.mu.Lock()
+	if src.checked != nil && time.Since(src.fetchedAt) < reuse {
+		checked, fetchedAt := src.checked, src.fetchedAt
+		src.mu.Unlock()
+		return checked, fetchedAt, nil
+	}
+
+	call := src.inflight
+	if call == nil {
+		call = &sourceCall{done: make(chan struct{})}
+		src.inflight = call
+
+		var shared context.Context
+		shared, call.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		go src.run(shared, call, download, process)
+	}
+	call.waiters++
+	src.mu.Unlock()
+
+	select {
+	case <-call.done:
+		src.leave(call)
+		return call.checked, call.fetchedAt, call.err
+	case <-ctx.Done():
+		src.leave(call)
+		return nil, time.Time{}, ctx.Err()
+	}
+}
+
+// leave mencatat bahwa satu pemanggil berhenti menunggu call. Kalau tidak ada lagi yang menunggu,
+// download dibatalkan dan call dilepas supaya pemanggil berikutnya memulai download baru.
+func (src *source) leave(call *sourceCall) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+
+	call.cancel()
+	if src.inflight == call {
+		src.inflight = nil
+	}
+}
+
+// run menjalankan satu download untuk call, memproses payload-nya sekali, lalu membagikan hasilnya ke semua yang menunggu.
+func (src *source) run(ctx context.Context, call *sourceCall, download func(ctx context.Context, url string) ([]byte, error), process func(items []rawStation, payload []byte, fetchedAt time.Time) map[string]checkedPayload) {
+	defer call.cancel()
+
+	var items []rawStation
+	payload, err := download(ctx, src.url)
+	if err == nil {
+		err = json.Unmarshal(payload, &items)
+	}
+	call.err = err
+	fetchedAt := time.Now()
+	call.fetchedAt = fetchedAt
+	if err == nil {
+		call.checked = process(items, payload, fetchedAt)
+	}
+
+	src.mu.Lock()
+	if src.inflight == call {
+		src.inflight = nil
+	}
+	if err == nil {
+		src.checked = call.checked
+		src.fetchedAt = fetchedAt
+	}
+	src.mu.Unlock()
+
+	close(call.done)
+}
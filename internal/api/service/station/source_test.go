@@ -0,0 +1,285 @@
+package station
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeUpstream adalah download palsu untuk source. payloads[i] dikembalikan untuk download ke-i
+// (payload terakhir dipakai ulang), errs[i] menggantikannya kalau diisi.
+// Kalau gate di-set, download menunggu gate ditutup atau ctx-nya dibatalkan.
+type fakeUpstream struct {
+	payloads []string
+	errs     []error
+	gate     chan struct{}
+
+	downloads atomic.Int32
+	processed atomic.Int32
+	canceled  atomic.Int32
+}
+
+func (f *fakeUpstream) download(ctx context.Context, url string) ([]byte, error) {
+	n := int(f.downloads.Add(1))
+
+	if f.gate != nil {
+		select {
+		case <-f.gate:
+		case <-ctx.Done():
+			f.canceled.Add(1)
+			return nil, ctx.Err()
+		}
+	}
+	if n <= len(f.errs) && f.errs[n-1] != nil {
+		return nil, f.errs[n-1]
+	}
+
+	return []byte(f.payloads[min(n, len(f.payloads))-1]), nil
+}
+
+func (f *fakeUpstream) process(items []rawStation, payload []byte, fetchedAt time.Time) map[string]checkedPayload {
+	f.processed.Add(1)
+	return map[string]checkedPayload{ResourceStations: {items: items}}
+}
+
+// get memanggil src.get dan mengembalikan nid record pertama untuk resource stations.
+func (f *fakeUpstream) get(ctx context.Context, src *source, reuse time.Duration) (string, time.Time, error) {
+	checked, fetchedAt, err := src.get(ctx, reuse, f.download, f.process)
+	if err != nil {
+		return "", fetchedAt, err
+	}
+
+	return checked[ResourceStations].items[0].ID, fetchedAt, nil
+}
+
+// waitForWaiters menunggu sampai download yang sedang berjalan di src punya n pemanggil yang menunggu.
+func waitForWaiters(t *testing.T, src *source, n int) {
+	t.Helper()
+	waitFor(t, func() bool {
+		src.mu.Lock()
+		defer src.mu.Unlock()
+		return src.inflight != nil && src.inflight.waiters == n
+	})
+}
+
+func TestSourceReuseWindow(t *testing.T) {
+	errDown := errors.New("upstream down")
+
+	tests := []struct {
+		name          string
+		reuse         time.Duration
+		wait          time.Duration // jeda antara panggilan pertama dan kedua
+		errs          []error
+		wantFirstErr  bool
+		wantSecond    string
+		wantDownloads int32
+		wantProcessed int32
+	}{
+		{name: "payload reused inside window", reuse: time.Minute, wantSecond: "1", wantDownloads: 1, wantProcessed: 1},
+		{name: "window 0 always downloads", reuse: 0, wantSecond: "2", wantDownloads: 2, wantProcessed: 2},
+		{name: "payload downloaded again after window", reuse: 30 * time.Millisecond, wait: 60 * time.Millisecond, wantSecond: "2", wantDownloads: 2, wantProcessed: 2},
+		{name: "failed download is not reused", reuse: time.Minute, errs: []error{errDown}, wantFirstErr: true, wantSecond: "2", wantDownloads: 2, wantProcessed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &fakeUpstream{payloads: []string{`[{"nid":"1"}]`, `[{"nid":"2"}]`}, errs: tt.errs}
+			src := &source{url: "http://upstream/stasiuns"}
+
+			first, firstAt, err := up.get(context.Background(), src, tt.reuse)
+			if (err != nil) != tt.wantFirstErr {
+				t.Fatalf("first get() = %q, %v; wantErr %v", first, err, tt.wantFirstErr)
+			}
+
+			time.Sleep(tt.wait)
+			second, secondAt, err := up.get(context.Background(), src, tt.reuse)
+			if err != nil || second != tt.wantSecond {
+				t.Fatalf("second get() = %q, %v; want %q", second, err, tt.wantSecond)
+			}
+			// Payload yang dipakai ulang membawa waktu unduh aslinya
+			if reused := second == first; reused != secondAt.Equal(firstAt) {
+				t.Errorf("fetchedAt first %v, second %v; reused %v", firstAt, secondAt, reused)
+			}
+
+			if got := up.downloads.Load(); got != tt.wantDownloads {
+				t.Errorf("downloads = %d, want %d", got, tt.wantDownloads)
+			}
+			if got := up.processed.Load(); got != tt.wantProcessed {
+				t.Errorf("processed = %d, want %d", got, tt.wantProcessed)
+			}
+		})
+	}
+}
+
+func TestSourceMalformedPayload(t *testing.T) {
+	up := &fakeUpstream{payloads: []string{`<html>maintenance</html>`}}
+	src := &source{url: "http://upstream/stasiuns"}
+
+	if _, _, err := up.get(context.Background(), src, time.Minute); err == nil {
+		t.Fatal("get() error = nil, want decode error")
+	}
+	if got := up.processed.Load(); got != 0 {
+		t.Errorf("processed = %d, want 0 for a payload that failed to decode", got)
+	}
+}
+
+func TestSourceSharesConcurrentDownload(t *testing.T) {
+	const callers = 6
+
+	up := &fakeUpstream{payloads: []string{`[{"nid":"1"}]`}, gate: make(chan struct{})}
+	src := &source{url: "http://upstream/stasiuns"}
+
+	var wg sync.WaitGroup
+	results := make(chan string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, _, err := up.get(context.Background(), src, time.Minute)
+			if err != nil {
+				id = "error: " + err.Error()
+			}
+			results <- id
+		}()
+	}
+
+	waitForWaiters(t, src, callers)
+	close(up.gate)
+	wg.Wait()
+	close(results)
+
+	for id := range results {
+		if id != "1" {
+			t.Errorf("caller got %q, want payload of download 1", id)
+		}
+	}
+	if got := up.downloads.Load(); got != 1 {
+		t.Errorf("downloads = %d, want 1", got)
+	}
+	if got := up.processed.Load(); got != 1 {
+		t.Errorf("processed = %d, want 1", got)
+	}
+}
+
+func TestServiceSharesSourcePerURL(t *testing.T) {
+	const payload = `[{"nid":"38","title":"Lebak Bulus","jadwal_hi_biasa":"05:00","estimasi":[{"stasiun_nid":"38","tarif":"Rp 3.000","waktu":"1 menit"}]}]`
+
+	tests := []struct {
+		name     string
+		fareURL  string // kosong = sama dengan apiURL
+		wantHits map[string]int32
+	}{
+		{name: "one URL for all resources", wantHits: map[string]int32{"/stasiuns": 1}},
+		{name: "separate fare URL", fareURL: "/tarif", wantHits: map[string]int32{"/stasiuns": 1, "/tarif": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			hits := map[string]int32{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				hits[r.URL.Path]++
+				mu.Unlock()
+				io.WriteString(w, payload)
+			}))
+			defer server.Close()
+
+			opts := []Option{WithReuseWindow(time.Minute)}
+			if tt.fareURL != "" {
+				opts = append(opts, WithResourceURL(ResourceFares, server.URL+tt.fareURL))
+			}
+			svc := NewService(time.Second, server.URL+"/stasiuns", opts...)
+
+			ctx := context.Background()
+			if _, err := svc.FetchStations(ctx); err != nil {
+				t.Fatalf("FetchStations() error = %v", err)
+			}
+			if _, err := svc.FetchSchedules(ctx); err != nil {
+				t.Fatalf("FetchSchedules() error = %v", err)
+			}
+			if _, err := svc.FetchFares(ctx); err != nil {
+				t.Fatalf("FetchFares() error = %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !maps.Equal(hits, tt.wantHits) {
+				t.Errorf("upstream hits = %v, want %v", hits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestSourceWaiterCancel(t *testing.T) {
+	tests := []struct {
+		name          string
+		cancelAll     bool
+		wantCanceled  int32
+		wantDownloads int32 // termasuk panggilan susulan setelah semua pemanggil pergi
+		wantNext      string
+	}{
+		{name: "download continues while another caller waits", cancelAll: false, wantCanceled: 0, wantDownloads: 1, wantNext: "1"},
+		{name: "download canceled after last caller leaves", cancelAll: true, wantCanceled: 1, wantDownloads: 2, wantNext: "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up := &fakeUpstream{payloads: []string{`[{"nid":"1"}]`, `[{"nid":"2"}]`}, gate: make(chan struct{})}
+			src := &source{url: "http://upstream/stasiuns"}
+
+			leaving, leave := context.WithCancel(context.Background())
+			staying, stay := context.WithCancel(context.Background())
+			defer stay()
+
+			type result struct {
+				id  string
+				err error
+			}
+			results := make(chan result, 2)
+			for _, ctx := range []context.Context{leaving, staying} {
+				go func(ctx context.Context) {
+					id, _, err := up.get(ctx, src, time.Minute)
+					results <- result{id, err}
+				}(ctx)
+			}
+			waitForWaiters(t, src, 2)
+
+			leave()
+			if res := <-results; !errors.Is(res.err, context.Canceled) {
+				t.Fatalf("canceled caller error = %v, want context.Canceled", res.err)
+			}
+
+			if tt.cancelAll {
+				stay()
+				waitFor(t, func() bool { return up.canceled.Load() == 1 })
+				if res := <-results; !errors.Is(res.err, context.Canceled) {
+					t.Errorf("second caller error = %v, want context.Canceled", res.err)
+				}
+				close(up.gate)
+			} else {
+				close(up.gate)
+				if res := <-results; res.err != nil || res.id != "1" {
+					t.Errorf("waiting caller got %q, %v; want payload of download 1", res.id, res.err)
+				}
+			}
+
+			// Panggilan berikutnya memakai payload yang sudah ada, atau memulai download baru kalau yang lama dibatalkan
+			next, _, err := up.get(context.Background(), src, time.Minute)
+			if err != nil || next != tt.wantNext {
+				t.Errorf("next get() = %q, %v; want %q", next, err, tt.wantNext)
+			}
+
+			if got := up.canceled.Load(); got != tt.wantCanceled {
+				t.Errorf("canceled downloads = %d, want %d", got, tt.wantCanceled)
+			}
+			if got := up.downloads.Load(); got != tt.wantDownloads {
+				t.Errorf("downloads = %d, want %d", got, tt.wantDownloads)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package station
+
+import (
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// Clock adalah sumber waktu "sekarang" untuk usecase.
+// Di production pakai jam sistem, tapi bisa diganti (misalnya FixedClock)
+// supaya hasil jadwal bisa dihitung untuk waktu tertentu secara deterministik.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock membaca jam sistem.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// fixedClock selalu mengembalikan waktu yang sama.
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// FixedClock membuat Clock yang selalu mengembalikan t.
+func FixedClock(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+// Format yang diterima parameter ?at=, dicoba berurutan.
+// Format tanpa offset dibaca di zona waktu operator.
+var atLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// ParseAt mengubah nilai parameter ?at= menjadi time.Time di zona waktu now.
+// Format yang didukung:
+// - RFC3339 ("2025-01-05T23:50:00+07:00")
+// - tanggal + jam tanpa offset ("2025-01-05T23:50", "2025-01-05 23:50")
+// - jam saja ("23:50") → dianggap tanggal hari ini (menurut now)
+func ParseAt(raw string, now time.Time) (time.Time, error) {
+	loc := now.Location()
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.In(loc), nil
+	}
+
+	for _, layout := range atLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, err := time.ParseInLocation("15:04", raw, loc); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, loc), nil
+	}
+
+	return time.Time{}, apperror.InvalidInput(apperror.CodeInvalidAt, "invalid at, use RFC3339 (2025-01-05T23:50:00+07:00) or HH:MM")
+}
@@ -0,0 +1,168 @@
+package station
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// stubService mengembalikan data tetap, supaya usecase bisa dites tanpa API MRT.
+type stubService struct {
+	schedules []station.ScheduleIn
+	fares     []station.FareIn
+}
+
+func (s stubService) FetchStations(context.Context) ([]station.StationIn, error) {
+	return nil, nil
+}
+
+func (s stubService) FetchSchedules(context.Context) ([]station.ScheduleIn, error) {
+	return s.schedules, nil
+}
+
+func (s stubService) FetchFares(context.Context) ([]station.FareIn, error) {
+	return s.fares, nil
+}
+
+var lebakBulus = station.ScheduleIn{
+	IDStasiun:             "38",
+	NamaStasiun:           "Stasiun Lebak Bulus",
+	JadwalBundaranHIBiasa: "05:00,07:00,07:05,07:10,07:15,23:50,24:10",
+	JadwalBundaranHILibur: "06:00,08:00,08:10",
+}
+
+func wib(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, DefaultLocation)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestFixedClock(t *testing.T) {
+	at := wib("2026-10-19 07:02")
+	clock := FixedClock(at)
+
+	for i := 0; i < 3; i++ {
+		if got := clock.Now(); !got.Equal(at) {
+			t.Fatalf("Now() call %d = %v, want %v", i, got, at)
+		}
+	}
+}
+
+func TestParseAt(t *testing.T) {
+	now := wib("2026-10-19 07:02")
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "rfc3339 in operator zone", raw: "2025-01-05T23:50:00+07:00", want: wib("2025-01-05 23:50")},
+		{name: "rfc3339 other offset", raw: "2025-01-05T16:50:00Z", want: wib("2025-01-05 23:50")},
+		{name: "date and time with T", raw: "2025-01-05T23:50", want: wib("2025-01-05 23:50")},
+		{name: "date and time with space", raw: "2025-01-05 23:50", want: wib("2025-01-05 23:50")},
+		{name: "clock only uses today", raw: "23:50", want: wib("2026-10-19 23:50")},
+		{name: "garbage", raw: "tomorrow", wantErr: true},
+		{name: "hour out of range", raw: "25:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAt(tt.raw, now)
+			if tt.wantErr {
+				appErr, ok := apperror.As(err)
+				if !ok || appErr.Code != apperror.CodeInvalidAt {
+					t.Fatalf("ParseAt(%q) error = %v, want %s", tt.raw, err, apperror.CodeInvalidAt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAt(%q) error = %v", tt.raw, err)
+			}
+			if !got.Equal(tt.want) || got.Location() != DefaultLocation {
+				t.Errorf("ParseAt(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTrainUsesInjectedClock(t *testing.T) {
+	tests := []struct {
+		name      string
+		now       string
+		query     NextTrainQuery
+		want      []string
+		wantDay   string
+		wantFirst int // detik_lagi kereta pertama
+	}{
+		{
+			name:      "weekday morning",
+			now:       "2026-10-19 07:02", // Senin
+			query:     NextTrainQuery{ID: "38", Destination: "HI"},
+			want:      []string{"07:05", "07:10", "07:15"},
+			wantDay:   DayTypeBiasa,
+			wantFirst: 180,
+		},
+		{
+			name:      "weekend uses libur table",
+			now:       "2026-10-18 07:59", // Minggu
+			query:     NextTrainQuery{ID: "38", Destination: "HI"},
+			want:      []string{"08:00", "08:10", "05:00"}, // lanjut ke Senin (biasa)
+			wantDay:   DayTypeLibur,
+			wantFirst: 60,
+		},
+		{
+			name:      "after midnight still on previous service day",
+			now:       "2026-10-20 00:05",
+			query:     NextTrainQuery{ID: "38", Destination: "HI", Limit: intPtr(1)},
+			want:      []string{"00:10"},
+			wantDay:   DayTypeBiasa,
+			wantFirst: 300,
+		},
+		{
+			name:      "at overrides clock",
+			now:       "2026-10-19 07:02",
+			query:     NextTrainQuery{ID: "38", Destination: "HI", At: "2026-10-19T07:12", Limit: intPtr(1)},
+			want:      []string{"07:15"},
+			wantDay:   DayTypeBiasa,
+			wantFirst: 180,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUsecase(stubService{schedules: []station.ScheduleIn{lebakBulus}}, WithClock(FixedClock(wib(tt.now))))
+
+			// Dua kali panggil dengan jam yang sama harus menghasilkan jawaban yang sama
+			for i := 0; i < 2; i++ {
+				resp, err := u.GetNextTrainByStation(context.Background(), tt.query)
+				if err != nil {
+					t.Fatalf("GetNextTrainByStation() error = %v", err)
+				}
+				if resp.JenisHari != tt.wantDay {
+					t.Errorf("JenisHari = %q, want %q", resp.JenisHari, tt.wantDay)
+				}
+
+				var got []string
+				for _, train := range resp.KeretaBerikutnya {
+					got = append(got, train.WaktuKeberangkatan)
+				}
+				if len(got) != len(tt.want) {
+					t.Fatalf("departures = %v, want %v", got, tt.want)
+				}
+				for j := range got {
+					if got[j] != tt.want[j] {
+						t.Fatalf("departures = %v, want %v", got, tt.want)
+					}
+				}
+				if first := resp.KeretaBerikutnya[0].DetikLagi; first != tt.wantFirst {
+					t.Errorf("DetikLagi = %d, want %d", first, tt.wantFirst)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,38 @@
 package station
 
+import "time"
+
+// DefaultLocation adalah zona waktu operator (WIB, UTC+7) yang dipakai
+// kalau usecase tidak diberi zona waktu lain lewat WithLocation.
+var DefaultLocation = time.FixedZone("WIB", 7*60*60)
+
 var DestinationMap = map[string]string{
 	"LB": "Lebak Bulus",
 	"HI": "Bundaran HI",
 }
+
+// Jumlah kereta yang dikembalikan oleh next-train.
+// Tanpa limit, default 3 kereta; kalau memakai jendela waktu (from/until) default-nya
+// semua kereta dalam jendela, tetap dibatasi MaxNextTrainLimit.
+const (
+	DefaultNextTrainLimit = 3
+	MaxNextTrainLimit     = 30
+)
+
+// Directions adalah urutan arah yang dipakai saat menampilkan jadwal kedua arah.
+var Directions = []string{"LB", "HI"}
+
+// Jenis hari untuk memilih tabel jadwal (Biasa / Libur) dari API MRT.
+const (
+	DayTypeBiasa = "biasa" // hari kerja
+	DayTypeLibur = "libur" // akhir pekan dan hari libur nasional
+)
+
+// Mode tampilan jadwal lengkap stasiun.
+const (
+	TimetableModeRemaining = "remaining" // hanya keberangkatan yang belum lewat
+	TimetableModeFull      = "full"      // semua keberangkatan dalam satu hari
+)
+
+// MaxTripLegs adalah jumlah perjalanan maksimal dalam satu permintaan estimasi biaya.
+const MaxTripLegs = 50
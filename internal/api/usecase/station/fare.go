@@ -0,0 +1,223 @@
+package station
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/farerule"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/utils"
+)
+
+// CurrencyIDR adalah kode mata uang (ISO 4217) untuk semua tarif MRT Jakarta.
+const CurrencyIDR = "IDR"
+
+// DataQualityError menandakan data dari upstream tidak bisa dipakai,
+// misalnya tarif atau waktu tempuh yang bukan angka. Error ini berarti masalah
+// ada di sumber data, bukan di request dari client.
+type DataQualityError struct {
+	StationID string // stasiun asal data
+	Field     string // nama field upstream, contoh: "estimasi.tarif"
+	Value     string // nilai mentah yang gagal diproses
+}
+
+func (e *DataQualityError) Error() string {
+	return fmt.Sprintf("data quality error: invalid %s %q for station %s", e.Field, e.Value, e.StationID)
+}
+
+// dataQualityError membungkus DataQualityError sebagai error domain KindUpstreamMalformed (502).
+func dataQualityError(stationID, field, value string) error {
+	return apperror.Wrap(apperror.KindUpstreamMalformed, apperror.CodeDataQuality, &DataQualityError{
+		StationID: stationID,
+		Field:     field,
+		Value:     value,
+	})
+}
+
+// FareRules adalah mesin aturan tarif per kategori penumpang (lihat package farerule).
+type FareRules interface {
+	Apply(riderType string, base int64, on time.Time) (farerule.Result, error)
+}
+
+// applyFareRules menghitung potongan tarif untuk kategori penumpang riderType pada perjalanan tanggal on.
+// Tanpa FareRules, hanya kategori default ("umum") tanpa potongan yang diterima.
+// Kategori yang dikenal tapi tidak berlaku pada tanggal on (misalnya promo yang sudah habis) tidak ditolak:
+// tarifnya dihitung dengan kategori umum, dan kategori yang diminta dicatat di KategoriDiminta.
+func (u *usecase) applyFareRules(riderType string, base int64, on time.Time) (FareRuleOut, error) {
+	result := farerule.Result{
+		RiderType: farerule.RiderType{ID: farerule.DefaultRiderType, Name: "Umum"},
+		Base:      base,
+		Final:     base,
+	}
+
+	var requested string
+	if u.rules != nil {
+		var err error
+		result, err = u.rules.Apply(riderType, base, on)
+		if errors.Is(err, farerule.ErrNotValidOnDate) {
+			requested = riderType
+			result, err = u.rules.Apply(farerule.DefaultRiderType, base, on)
+		}
+		if err != nil {
+			return FareRuleOut{}, apperror.Wrap(apperror.KindInvalidInput, apperror.CodeInvalidRiderType, err)
+		}
+	} else if riderType != "" && riderType != farerule.DefaultRiderType {
+		return FareRuleOut{}, apperror.InvalidInput(apperror.CodeInvalidRiderType, "unknown rider_type "+strconv.Quote(riderType))
+	}
+
+	out := ConvertFareRuleResult(result)
+	out.KategoriDiminta = requested
+	return out, nil
+}
+
+// ConvertFareRuleResult mengubah hasil farerule menjadi FareRuleOut.
+func ConvertFareRuleResult(result farerule.Result) FareRuleOut {
+	return FareRuleOut{
+		KategoriPenumpang:  result.RiderType.ID,
+		NamaKategori:       result.RiderType.Name,
+		TarifDasar:         result.Base,
+		Diskon:             result.Discount,
+		TarifAkhir:         result.Final,
+		TarifAkhirTampilan: FormatRupiah(result.Final),
+		BatasHarian:        result.RiderType.DailyCap,
+	}
+}
+
+// GetFareMatrix membangun matriks tarif dan durasi antar semua stasiun (atau sebagian, lewat query.StationIDs)
+// dari satu kali FetchFares. Baris dan kolom diurutkan sesuai urutan stasiun di lintasan.
+// Sel yang tarif atau waktunya tidak terbaca dari upstream dikosongkan (null), bukan menggagalkan seluruh matriks;
+// data seperti itu sudah dilaporkan di data quality report.
+func (u *usecase) GetFareMatrix(ctx context.Context, query FareMatrixQuery) (*FareMatrixOut, error) {
+	fares, err := u.service.FetchFares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Urutkan sesuai lintasan kalau bisa, supaya matriks mudah dibaca
+	ordered := fares
+	if line, err := BuildLine(fares); err == nil {
+		byID := make(map[string]station.FareIn, len(fares))
+		for _, st := range fares {
+			byID[st.ID] = st
+		}
+		ordered = make([]station.FareIn, 0, len(fares))
+		for _, st := range line.Stations {
+			ordered = append(ordered, byID[st.ID])
+		}
+	}
+
+	if len(query.StationIDs) > 0 {
+		wanted := make(map[string]bool, len(query.StationIDs))
+		for _, id := range query.StationIDs {
+			wanted[id] = true
+		}
+		ordered = utils.Filter(ordered, func(st station.FareIn) bool {
+			return wanted[st.ID]
+		})
+		if len(ordered) != len(wanted) {
+			return nil, apperror.NotFound(apperror.CodeStationNotFound, "station not found")
+		}
+	}
+
+	resp := &FareMatrixOut{MataUang: CurrencyIDR}
+	for _, from := range ordered {
+		resp.Stasiun = append(resp.Stasiun, StationOut{Id: from.ID, Nama: from.Nama})
+
+		estimasiByID := make(map[string]station.EstimasiIn, len(from.Estimasi))
+		for _, e := range from.Estimasi {
+			estimasiByID[e.IDStasiunTujuan] = e
+		}
+
+		row := make([]*FareCellOut, 0, len(ordered))
+		for _, to := range ordered {
+			e, ok := estimasiByID[to.ID]
+			if !ok || to.ID == from.ID {
+				row = append(row, nil)
+				continue
+			}
+
+			amount, err := ConvertFareAmount(from.ID, e)
+			if err != nil {
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, &FareCellOut{
+				TarifRupiah: amount.TarifRupiah,
+				DurasiMenit: amount.DurasiMenit,
+			})
+		}
+		resp.Matriks = append(resp.Matriks, row)
+	}
+
+	return resp, nil
+}
+
+// ParseRupiah mengubah tarif mentah dari upstream menjadi angka rupiah.
+// Menerima "14000", "14.000", "14,000", "Rp 14.000" atau "IDR 14000".
+func ParseRupiah(raw string) (int64, error) {
+	cleaned := strings.TrimSpace(raw)
+	cleaned = strings.TrimPrefix(strings.TrimPrefix(cleaned, "Rp"), "IDR")
+	cleaned = strings.NewReplacer(".", "", ",", "", " ", "").Replace(cleaned)
+
+	amount, err := strconv.ParseInt(cleaned, 10, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid rupiah amount %q", raw)
+	}
+
+	return amount, nil
+}
+
+// ParseMinutes mengubah waktu tempuh mentah dari upstream ("10" atau "10 menit") menjadi menit.
+func ParseMinutes(raw string) (int, error) {
+	cleaned := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw), "menit"))
+
+	minutes, err := strconv.Atoi(cleaned)
+	if err != nil || minutes < 0 {
+		return 0, fmt.Errorf("invalid minutes %q", raw)
+	}
+
+	return minutes, nil
+}
+
+// FormatRupiah membuat tampilan tarif, contoh: 14000 → "Rp 14.000".
+func FormatRupiah(amount int64) string {
+	digits := strconv.FormatInt(amount, 10)
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	return "Rp " + b.String()
+}
+
+// ConvertFareAmount mengubah EstimasiIn milik stasiun fromId menjadi FareAmountOut bertipe.
+// Kalau tarif atau waktu tempuh tidak bisa di-parse, kembalikan *DataQualityError.
+func ConvertFareAmount(fromId string, estimasi station.EstimasiIn) (FareAmountOut, error) {
+	tarif, err := ParseRupiah(estimasi.Tarif)
+	if err != nil {
+		return FareAmountOut{}, dataQualityError(fromId, "estimasi.tarif", estimasi.Tarif)
+	}
+
+	minutes, err := ParseMinutes(estimasi.Waktu)
+	if err != nil {
+		return FareAmountOut{}, dataQualityError(fromId, "estimasi.waktu", estimasi.Waktu)
+	}
+
+	return FareAmountOut{
+		Tarif:       FormatRupiah(tarif),
+		TarifRupiah: tarif,
+		MataUang:    CurrencyIDR,
+		Durasi:      strconv.Itoa(minutes) + " menit",
+		DurasiMenit: minutes,
+		DurasiDetik: minutes * 60,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package station
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+)
+
+func TestGetFareMatrix(t *testing.T) {
+	fares := []station.FareIn{
+		{ID: "38", Nama: "Lebak Bulus", Estimasi: []station.EstimasiIn{
+			{IDStasiunTujuan: "21", Tarif: "Rp 14.000", Waktu: "30 menit"},
+			{IDStasiunTujuan: "30", Tarif: "gratis", Waktu: "25 menit"},
+		}},
+		{ID: "21", Nama: "Bundaran HI", Estimasi: []station.EstimasiIn{
+			{IDStasiunTujuan: "38", Tarif: "Rp 14.000", Waktu: "30 menit"},
+			{IDStasiunTujuan: "30", Tarif: "Rp 4.000", Waktu: "-"},
+		}},
+		{ID: "30", Nama: "Istora", Estimasi: []station.EstimasiIn{
+			{IDStasiunTujuan: "38", Tarif: "Rp 12.000", Waktu: "25 menit"},
+		}},
+	}
+
+	// want[from][to]: tarif yang diharapkan, 0 = sel null
+	want := map[string]map[string]int64{
+		"38": {"21": 14000},
+		"21": {"38": 14000},
+		"30": {"38": 12000},
+	}
+
+	u := NewUsecase(stubService{fares: fares})
+	resp, err := u.GetFareMatrix(context.Background(), FareMatrixQuery{})
+	if err != nil {
+		t.Fatalf("GetFareMatrix() error = %v, want malformed cells left empty", err)
+	}
+	if len(resp.Stasiun) != len(fares) || len(resp.Matriks) != len(fares) {
+		t.Fatalf("GetFareMatrix() = %d stations, %d rows; want %d", len(resp.Stasiun), len(resp.Matriks), len(fares))
+	}
+
+	for i, from := range resp.Stasiun {
+		for j, to := range resp.Stasiun {
+			t.Run(from.Id+"->"+to.Id, func(t *testing.T) {
+				cell := resp.Matriks[i][j]
+				wantTarif := want[from.Id][to.Id]
+				switch {
+				case wantTarif == 0 && cell != nil:
+					t.Errorf("cell = %+v, want null", cell)
+				case wantTarif != 0 && (cell == nil || cell.TarifRupiah != wantTarif):
+					t.Errorf("cell = %+v, want tarif %d", cell, wantTarif)
+				}
+			})
+		}
+	}
+}
@@ -1,68 +1,130 @@
 package station
 
 import (
-	"errors"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/timetable"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
 )
 
-func ConvertDataToResponse(schedule station.ScheduleIn) (resp []ScheduleOut, err error) {
-	scheduleLebakBulusParsed, err := ConvertScheduleToTimeFormat(schedule.JadwalLebakBulusBiasa)
-	if err != nil {
-		return
+// ConvertDataToResponse mengubah jadwal mentah satu stasiun menjadi daftar keberangkatan
+// kedua arah pada hari operasional day yang belum lewat dari waktu "now", diurutkan berdasarkan waktu.
+func ConvertDataToResponse(schedule station.ScheduleIn, day ServiceDay, now time.Time) (resp []ScheduleOut, err error) {
+	for _, destination := range Directions {
+		departures, err := ConvertDirectionSchedule(schedule, destination, day, now, true)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, departures...)
 	}
 
-	scheduleBundaranHIParsed, err := ConvertScheduleToTimeFormat(schedule.JadwalBundaranHIBiasa)
+	// Timestamp semua item berasal dari zona waktu yang sama,
+	// jadi urutan string RFC3339 sama dengan urutan waktunya.
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].Timestamp < resp[j].Timestamp
+	})
+
+	return
+}
+
+// ConvertDirectionSchedule mengubah jadwal satu arah (destination "LB"/"HI") pada hari
+// operasional day menjadi daftar ScheduleOut yang terurut. Kalau remainingOnly true,
+// keberangkatan yang sudah lewat dari now dibuang.
+func ConvertDirectionSchedule(schedule station.ScheduleIn, destination string, day ServiceDay, now time.Time, remainingOnly bool) ([]ScheduleOut, error) {
+	times, err := day.Departures(schedule, destination)
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	for _, item := range scheduleLebakBulusParsed {
-		if item.Format("15:04") > time.Now().Format("15:04") {
-			resp = append(resp, ScheduleOut{
-				NamaStasiun: schedule.NamaStasiun,
-				Waktu:       item.Format("15:04"),
-			})
+	resp := []ScheduleOut{}
+	for _, item := range times {
+		if remainingOnly && !item.After(now) {
+			continue
 		}
+		resp = append(resp, ScheduleOut{
+			NamaStasiun: schedule.NamaStasiun,
+			Arah:        destination,
+			Tujuan:      DestinationMap[destination],
+			Waktu:       item.Format("15:04"),
+			Timestamp:   item.Format(time.RFC3339),
+		})
 	}
 
-	for _, item := range scheduleBundaranHIParsed {
-		if item.Format("15:04") > time.Now().Format("15:04") {
-			resp = append(resp, ScheduleOut{
-				NamaStasiun: schedule.NamaStasiun,
-				Waktu:       item.Format("15:04"),
-			})
-		}
+	return resp, nil
+}
+
+// ConvertScheduleToTimeFormat mengubah string jadwal mentah ("HH:MM:SS,HH:MM,...") menjadi time.Time
+// pada tanggal yang sama dengan day, di zona waktu day.Location(). Jam "24:xx" ke atas jatuh di tanggal berikutnya.
+// Entri yang tidak bisa di-parse dilewati dan dikembalikan di issues (lihat timetable.Parse).
+func ConvertScheduleToTimeFormat(schedule string, day time.Time) (resp []time.Time, issues []timetable.Issue) {
+	offsets, issues := timetable.Parse(schedule)
+
+	for _, offset := range offsets {
+		// time.Date menormalkan detik yang melewati 24 jam ke tanggal berikutnya
+		resp = append(resp, time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(offset/time.Second), 0, day.Location()))
 	}
 
-	return
+	return resp, issues
 }
 
-func ConvertScheduleToTimeFormat(schedule string) (resp []time.Time, err error) {
-	var schedules = strings.Split(schedule, ",")
-	now := time.Now()
-	today := now.Format("2006-01-02")
+// ConvertTrainSchedule membuat TrainSchedule untuk keberangkatan pada waktu departure,
+// lengkap dengan hitung mundur relatif terhadap waktu acuan now.
+func ConvertTrainSchedule(departure, now time.Time, dayType string) TrainSchedule {
+	remaining := departure.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
 
-	loc := time.Now().Location()
+	return TrainSchedule{
+		WaktuKeberangkatan: departure.Format("15:04"),
+		Tanggal:            departure.Format("2006-01-02"),
+		JenisHari:          dayType,
+		Timestamp:          departure.Format(time.RFC3339),
+		DetikLagi:          int(remaining / time.Second),
+		MenitLagi:          int(remaining / time.Minute),
+		Label:              CountdownLabel(remaining),
+	}
+}
 
-	for _, item := range schedules {
-		trimedTime := strings.TrimSpace(item)
-		if trimedTime == "" {
-			continue
-		}
+// CountdownLabel membuat label hitung mundur untuk papan informasi.
+// Kurang dari 1 menit → "Berangkat", kurang dari 1 jam → "N menit", selebihnya → "X jam Y menit".
+func CountdownLabel(remaining time.Duration) string {
+	minutes := int(remaining / time.Minute)
+	if minutes < 1 {
+		return "Berangkat"
+	}
+	if minutes < 60 {
+		return strconv.Itoa(minutes) + " menit"
+	}
 
-		fullTimeStr := today + " " + trimedTime
-		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", fullTimeStr, loc)
-		if err != nil {
-			err = errors.New("invalid time format " + trimedTime)
-			return nil, err
-		}
-		resp = append(resp, parsed)
+	label := strconv.Itoa(minutes/60) + " jam"
+	if minutes%60 != 0 {
+		label += " " + strconv.Itoa(minutes%60) + " menit"
 	}
 
-	return
+	return label
+}
+
+// SelectTimetable memilih string jadwal mentah sesuai arah tujuan ("LB"/"HI") dan jenis hari.
+func SelectTimetable(schedule station.ScheduleIn, destination, dayType string) (string, error) {
+	switch destination {
+	case "LB":
+		if dayType == DayTypeLibur {
+			return schedule.JadwalLebakBulusLibur, nil
+		}
+		return schedule.JadwalLebakBulusBiasa, nil
+	case "HI":
+		if dayType == DayTypeLibur {
+			return schedule.JadwalBundaranHILibur, nil
+		}
+		return schedule.JadwalBundaranHIBiasa, nil
+	default:
+		return "", apperror.InvalidInput(apperror.CodeInvalidDest, "invalid destination, use 'LB' or 'HI'")
+	}
 }
 
 func ParseAntarmoda(antarmodaStr string) []AntarmodaOut {
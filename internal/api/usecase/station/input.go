@@ -0,0 +1,67 @@
+package station
+
+// ScheduleQuery (Parameter Jadwal Keberangkatan Stasiun)
+type ScheduleQuery struct {
+	ID      string
+	At      string // Opsional, evaluasi jadwal pada waktu tertentu (lihat ParseAt)
+	DayType string // Opsional, paksa jenis hari "biasa" / "libur"
+}
+
+// TimetableQuery (Parameter Jadwal Lengkap Stasiun per Arah)
+type TimetableQuery struct {
+	ID      string
+	At      string // Opsional, evaluasi jadwal pada waktu tertentu (lihat ParseAt)
+	DayType string // Opsional, paksa jenis hari "biasa" / "libur"
+	Mode    string // "remaining" (default) atau "full"
+}
+
+// NextTrainQuery (Parameter Pencarian Kereta Berikutnya)
+type NextTrainQuery struct {
+	ID          string
+	Destination string
+	At          string // Opsional, evaluasi jadwal pada waktu tertentu (lihat ParseAt)
+	DayType     string // Opsional, paksa jenis hari "biasa" / "libur"
+	Limit       *int   // Opsional, jumlah kereta (1 - MaxNextTrainLimit), nil = default
+	From        string // Opsional, batas awal jendela waktu "HH:MM" pada hari operasional
+	Until       string // Opsional, batas akhir jendela waktu "HH:MM" pada hari operasional
+}
+
+// JourneyQuery (Parameter Rencana Perjalanan)
+type JourneyQuery struct {
+	From     string // ID stasiun asal
+	To       string // ID stasiun tujuan
+	DepartAt string // Opsional, waktu berangkat paling cepat (format sama dengan ParseAt)
+}
+
+// FareQuery (Parameter Tarif dan Durasi)
+type FareQuery struct {
+	From      string
+	To        string
+	RiderType string // Opsional, kategori penumpang (default "umum")
+}
+
+// FareMatrixQuery (Parameter Matriks Tarif)
+type FareMatrixQuery struct {
+	StationIDs []string // Opsional, hanya stasiun-stasiun ini yang masuk matriks
+}
+
+// TripCostQuery (Parameter Estimasi Biaya Perjalanan Multi-Leg)
+type TripCostQuery struct {
+	RiderType  string               // Opsional, kategori penumpang (default "umum")
+	Legs       []TripLegQuery       // 1 - MaxTripLegs perjalanan
+	Projection *TripProjectionQuery // Opsional, proyeksi biaya bulanan
+}
+
+// TripLegQuery (Satu Perjalanan dalam TripCostQuery)
+type TripLegQuery struct {
+	From string // ID stasiun asal
+	To   string // ID stasiun tujuan
+	Date string // Opsional "2006-01-02", default hari operasional saat ini
+}
+
+// TripProjectionQuery (Parameter Proyeksi Bulanan)
+// Semua leg dianggap sebagai pola perjalanan satu hari yang diulang setiap hari yang cocok.
+type TripProjectionQuery struct {
+	Month   string // "2006-01"
+	DayType string // Opsional, hanya hari "biasa" / "libur", kosong = semua hari
+}
@@ -0,0 +1,135 @@
+package station
+
+import (
+	"context"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// PlanJourney menggabungkan data tarif dan jadwal untuk perjalanan dari query.From ke query.To:
+// 1. Cari tarif dan lama perjalanan dari EstimasiIn stasiun asal.
+// 2. Tentukan arah kereta (LB/HI) dari urutan stasiun.
+// 3. Ambil keberangkatan berikutnya dari stasiun asal setelah depart_at.
+// 4. Hitung waktu tiba = waktu berangkat + lama perjalanan.
+func (u *usecase) PlanJourney(ctx context.Context, query JourneyQuery) (*JourneyOut, error) {
+	if fields := validateStationPair("from", "to", query.From, query.To); len(fields) > 0 {
+		return nil, apperror.Validation(fields...)
+	}
+
+	departAt, err := u.evaluationTime(query.DepartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	fares, err := u.service.FetchFares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFare, toFare, estimasi, err := findEstimasi(fares, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := ConvertFareAmount(fromFare.ID, estimasi)
+	if err != nil {
+		return nil, err
+	}
+	travelTime := time.Duration(amount.DurasiMenit) * time.Minute
+
+	line, err := BuildLine(fares)
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := line.Direction(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleSelected, err := u.scheduleByStation(ctx, query.From)
+	if err != nil {
+		return nil, err
+	}
+
+	day, err := u.serviceDay(departAt, "")
+	if err != nil {
+		return nil, err
+	}
+
+	departures, err := u.upcomingDepartures(scheduleSelected, direction, day, departAt, time.Time{}, DefaultNextTrainLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(departures) == 0 {
+		return nil, apperror.NoService(apperror.CodeNoNextTrain, "no next train available")
+	}
+
+	resp := &JourneyOut{
+		IdDari:        fromFare.ID,
+		Dari:          fromFare.Nama,
+		IdKe:          toFare.ID,
+		Ke:            toFare.Nama,
+		Arah:          direction,
+		Tujuan:        DestinationMap[direction],
+		FareAmountOut: amount,
+		WaktuAcuan:    departAt.Format(time.RFC3339),
+	}
+	for _, d := range departures {
+		arrival := d.time.Add(travelTime)
+		resp.Itinerari = append(resp.Itinerari, ItineraryOut{
+			Berangkat:          d.time.Format("15:04"),
+			Tiba:               arrival.Format("15:04"),
+			JenisHari:          d.dayType,
+			BerangkatTimestamp: d.time.Format(time.RFC3339),
+			TibaTimestamp:      arrival.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
+
+// validateStationPair mengecek pasangan ID stasiun asal/tujuan dari input client.
+// fromField dan toField adalah nama field yang dilaporkan di detail error (contoh: "legs[0].from").
+func validateStationPair(fromField, toField, fromId, toId string) []apperror.FieldError {
+	var fields []apperror.FieldError
+	if fromId == "" {
+		fields = append(fields, apperror.FieldError{Field: fromField, Code: apperror.CodeMissingStation, Message: fromField + " is required"})
+	}
+	if toId == "" {
+		fields = append(fields, apperror.FieldError{Field: toField, Code: apperror.CodeMissingStation, Message: toField + " is required"})
+	}
+	if fromId != "" && fromId == toId {
+		fields = append(fields, apperror.FieldError{Field: toField, Code: apperror.CodeSameStation, Message: toField + " must be different from " + fromField})
+	}
+
+	return fields
+}
+
+// findEstimasi mencari data stasiun asal, stasiun tujuan, dan estimasi perjalanan di antaranya.
+func findEstimasi(fares []station.FareIn, fromId, toId string) (from, to station.FareIn, estimasi station.EstimasiIn, err error) {
+	var foundFrom, foundTo bool
+	for _, st := range fares {
+		if st.ID == fromId {
+			from, foundFrom = st, true
+		}
+		if st.ID == toId {
+			to, foundTo = st, true
+		}
+	}
+	if !foundFrom || !foundTo {
+		err = apperror.NotFound(apperror.CodeStationNotFound, "station not found")
+		return
+	}
+
+	for _, e := range from.Estimasi {
+		if e.IDStasiunTujuan == toId {
+			return from, to, e, nil
+		}
+	}
+
+	err = apperror.NotFound(apperror.CodeFareNotFound, "fare/estimasi not found between stations")
+	return
+}
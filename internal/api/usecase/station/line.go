@@ -0,0 +1,74 @@
+package station
+
+import (
+	"context"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// GetLines mengembalikan daftar lintasan beserta terminus tiap arah.
+func (u *usecase) GetLines(ctx context.Context) ([]LineOut, error) {
+	line, err := u.line(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []LineOut{ConvertLineToResponse(line)}, nil
+}
+
+// GetLineStations mengembalikan urutan stasiun dalam lintasan beserta waktu tempuh antar stasiun.
+func (u *usecase) GetLineStations(ctx context.Context, id string) (*LineDetailOut, error) {
+	line, err := u.line(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if line.ID != id {
+		return nil, apperror.NotFound(apperror.CodeLineNotFound, "line not found")
+	}
+
+	resp := &LineDetailOut{LineOut: ConvertLineToResponse(line)}
+	for i, st := range line.Stations {
+		item := LineStationOut{
+			Urutan:        i + 1,
+			ID:            st.ID,
+			Nama:          st.Name,
+			WaktuDariAwal: st.MinutesFromStart,
+		}
+		if i+1 < len(line.Stations) {
+			next := line.Stations[i+1].MinutesFromStart - st.MinutesFromStart
+			item.WaktuKeBerikutnya = &next
+		}
+		resp.Stasiun = append(resp.Stasiun, item)
+	}
+
+	return resp, nil
+}
+
+// line menyusun model lintasan dari data tarif terbaru.
+func (u *usecase) line(ctx context.Context) (*Line, error) {
+	fares, err := u.service.FetchFares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildLine(fares)
+}
+
+// ConvertLineToResponse mengubah model Line menjadi LineOut.
+func ConvertLineToResponse(line *Line) LineOut {
+	resp := LineOut{
+		ID:            line.ID,
+		Nama:          line.Name,
+		JumlahStasiun: len(line.Stations),
+	}
+	for _, direction := range Directions {
+		terminus := line.Terminus(direction)
+		resp.Terminus = append(resp.Terminus, TerminusOut{
+			Arah:        direction,
+			IdStasiun:   terminus.ID,
+			NamaStasiun: terminus.Name,
+		})
+	}
+
+	return resp
+}
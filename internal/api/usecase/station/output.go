@@ -9,20 +9,135 @@ type StationOut struct {
 // ScheduleOut (Output Jadwal Per Keberangkatan)
 type ScheduleOut struct {
 	NamaStasiun string `json:"nama_stasiun"`
+	Arah        string `json:"arah"`   // "LB" atau "HI"
+	Tujuan      string `json:"tujuan"` // Contoh: "Lebak Bulus"
 	Waktu       string `json:"waktu"`
+	Timestamp   string `json:"timestamp"` // ISO-8601 dengan offset, contoh: "2025-01-02T05:30:00+07:00"
+}
+
+// TimetableOut (Output Jadwal Lengkap Stasiun per Arah)
+type TimetableOut struct {
+	IdStasiun   string               `json:"id_stasiun"`
+	NamaStasiun string               `json:"nama_stasiun"`
+	Tanggal     string               `json:"tanggal"`    // Tanggal hari operasional, contoh: "2025-01-05"
+	JenisHari   string               `json:"jenis_hari"` // "biasa" atau "libur"
+	Mode        string               `json:"mode"`       // "remaining" atau "full"
+	Arah        []DirectionTimetable `json:"arah"`
+}
+
+// DirectionTimetable (Sub-struct untuk Jadwal Satu Arah)
+type DirectionTimetable struct {
+	Kode          string        `json:"kode"`   // "LB" atau "HI"
+	Tujuan        string        `json:"tujuan"` // Contoh: "Lebak Bulus"
+	Keberangkatan []ScheduleOut `json:"keberangkatan"`
 }
 
 // FareOut (Output Tarif dan Durasi)
 type FareOut struct {
-	Dari   string `json:"dari"`
-	Ke     string `json:"ke"`
-	Tarif  string `json:"tarif"`
-	Durasi string `json:"durasi"` // Contoh: "10 menit"
+	Dari string `json:"dari"`
+	Ke   string `json:"ke"`
+	FareAmountOut
+	RincianTarif FareRuleOut `json:"rincian_tarif"`
+}
+
+// FareRuleOut (Sub-struct Potongan Tarif per Kategori Penumpang)
+type FareRuleOut struct {
+	KategoriPenumpang  string `json:"kategori_penumpang"` // Contoh: "umum", "pelajar"
+	NamaKategori       string `json:"nama_kategori"`
+	TarifDasar         int64  `json:"tarif_dasar"`
+	Diskon             int64  `json:"diskon"`
+	TarifAkhir         int64  `json:"tarif_akhir"`
+	TarifAkhirTampilan string `json:"tarif_akhir_tampilan"` // Contoh: "Rp 7.000"
+	BatasHarian        int64  `json:"batas_harian"`         // Batas total tarif per hari, 0 = tanpa batas
+
+	// Kategori yang diminta kalau tidak berlaku pada tanggal perjalanan (tarif dihitung dengan kategori umum)
+	KategoriDiminta string `json:"kategori_diminta,omitempty"`
+}
+
+// FareAmountOut (Sub-struct Tarif dan Durasi, field-nya ikut di-inline ke response induk)
+type FareAmountOut struct {
+	Tarif       string `json:"tarif"` // Tampilan, contoh: "Rp 14.000"
+	TarifRupiah int64  `json:"tarif_rupiah"`
+	MataUang    string `json:"mata_uang"` // Kode ISO 4217, selalu "IDR"
+	Durasi      string `json:"durasi"`    // Tampilan, contoh: "10 menit"
+	DurasiMenit int    `json:"durasi_menit"`
+	DurasiDetik int    `json:"durasi_detik"`
+}
+
+// FareMatrixOut (Output Matriks Tarif Asal–Tujuan)
+// Matriks[i][j] adalah tarif dari Stasiun[i] ke Stasiun[j], null pada diagonal atau kalau datanya tidak ada atau tidak terbaca.
+type FareMatrixOut struct {
+	MataUang string           `json:"mata_uang"`
+	Stasiun  []StationOut     `json:"stasiun"`
+	Matriks  [][]*FareCellOut `json:"matriks"`
+}
+
+// FareCellOut (Sub-struct untuk Satu Sel Matriks Tarif)
+type FareCellOut struct {
+	TarifRupiah int64 `json:"tarif_rupiah"`
+	DurasiMenit int   `json:"durasi_menit"`
+}
+
+// TripCostOut (Output Estimasi Biaya Perjalanan Multi-Leg)
+type TripCostOut struct {
+	KategoriPenumpang string             `json:"kategori_penumpang"`
+	MataUang          string             `json:"mata_uang"`
+	Perjalanan        []TripLegOut       `json:"perjalanan"`
+	PerHari           []TripDayOut       `json:"per_hari"`
+	TotalTarifDasar   int64              `json:"total_tarif_dasar"`
+	TotalDiskon       int64              `json:"total_diskon"` // Potongan kategori + potongan batas harian
+	TotalRupiah       int64              `json:"total_rupiah"`
+	Total             string             `json:"total"` // Contoh: "Rp 28.000"
+	TotalDurasiMenit  int                `json:"total_durasi_menit"`
+	Proyeksi          *TripProjectionOut `json:"proyeksi,omitempty"`
+
+	// Tanggal leg di luar masa berlaku kategori penumpang, dihitung dengan tarif umum ("2006-01-02")
+	TanggalTarifUmum []string `json:"tanggal_tarif_umum,omitempty"`
+}
+
+// TripLegOut (Sub-struct untuk Satu Perjalanan)
+type TripLegOut struct {
+	Urutan  int    `json:"urutan"` // Mulai dari 1, sesuai urutan di request
+	IdDari  string `json:"id_dari"`
+	Dari    string `json:"dari"`
+	IdKe    string `json:"id_ke"`
+	Ke      string `json:"ke"`
+	Tanggal string `json:"tanggal"` // "2006-01-02"
+	FareAmountOut
+	RincianTarif FareRuleOut `json:"rincian_tarif"`
+}
+
+// TripDayOut (Sub-struct Rekap Biaya per Tanggal)
+type TripDayOut struct {
+	Tanggal          string `json:"tanggal"`
+	JumlahPerjalanan int    `json:"jumlah_perjalanan"`
+	Subtotal         int64  `json:"subtotal"`     // Jumlah tarif akhir semua perjalanan di tanggal ini
+	BatasHarian      int64  `json:"batas_harian"` // 0 = tanpa batas
+	TotalRupiah      int64  `json:"total_rupiah"` // Subtotal setelah batas harian
+}
+
+// TripProjectionOut (Sub-struct Proyeksi Biaya Bulanan)
+type TripProjectionOut struct {
+	Bulan            string `json:"bulan"`      // "2006-01"
+	JenisHari        string `json:"jenis_hari"` // "biasa", "libur", atau "semua"
+	JumlahHari       int    `json:"jumlah_hari"`
+	TotalRupiah      int64  `json:"total_rupiah"`
+	Total            string `json:"total"`
+	TotalDurasiMenit int    `json:"total_durasi_menit"`
+
+	// Tanggal di luar masa berlaku kategori penumpang, dihitung dengan tarif umum ("2006-01-02")
+	TanggalTarifUmum []string `json:"tanggal_tarif_umum,omitempty"`
 }
 
 // TrainSchedule (Sub-struct untuk Waktu Keberangkatan)
 type TrainSchedule struct {
 	WaktuKeberangkatan string `json:"waktu_keberangkatan"`
+	Tanggal            string `json:"tanggal"`    // Tanggal keberangkatan, contoh: "2025-01-06"
+	JenisHari          string `json:"jenis_hari"` // Jenis hari tabel jadwal yang dipakai
+	Timestamp          string `json:"timestamp"`  // ISO-8601 dengan offset
+	DetikLagi          int    `json:"detik_lagi"` // Sisa detik sampai keberangkatan, dihitung dari waktu_acuan
+	MenitLagi          int    `json:"menit_lagi"` // Sisa menit (dibulatkan ke bawah)
+	Label              string `json:"label"`      // Contoh: "Berangkat", "2 menit", "1 jam 5 menit"
 }
 
 // NextTrainOut (Output Kereta Berikutnya)
@@ -30,9 +145,63 @@ type NextTrainOut struct {
 	IdKereta         string          `json:"id_kereta"`
 	Stasiun          string          `json:"stasiun"`
 	Tujuan           string          `json:"tujuan"`
+	JenisHari        string          `json:"jenis_hari"`  // Jenis hari operasional saat ini: "biasa" atau "libur"
+	WaktuAcuan       string          `json:"waktu_acuan"` // Waktu acuan hitung mundur (ISO-8601, zona waktu operator)
 	KeretaBerikutnya []TrainSchedule `json:"kereta_berikutnya"`
 }
 
+// JourneyOut (Output Rencana Perjalanan)
+type JourneyOut struct {
+	IdDari string `json:"id_dari"`
+	Dari   string `json:"dari"`
+	IdKe   string `json:"id_ke"`
+	Ke     string `json:"ke"`
+	Arah   string `json:"arah"`   // "LB" atau "HI"
+	Tujuan string `json:"tujuan"` // Terminus kereta yang dinaiki
+	FareAmountOut
+	WaktuAcuan string         `json:"waktu_acuan"` // Waktu berangkat paling cepat (ISO-8601)
+	Itinerari  []ItineraryOut `json:"itinerari"`
+}
+
+// ItineraryOut (Sub-struct untuk Satu Pilihan Keberangkatan)
+type ItineraryOut struct {
+	Berangkat          string `json:"berangkat"` // "15:04"
+	Tiba               string `json:"tiba"`      // "15:04"
+	JenisHari          string `json:"jenis_hari"`
+	BerangkatTimestamp string `json:"berangkat_timestamp"` // ISO-8601 dengan offset
+	TibaTimestamp      string `json:"tiba_timestamp"`      // ISO-8601 dengan offset
+}
+
+// LineOut (Output Ringkas Lintasan)
+type LineOut struct {
+	ID            string        `json:"id"`
+	Nama          string        `json:"nama"`
+	JumlahStasiun int           `json:"jumlah_stasiun"`
+	Terminus      []TerminusOut `json:"terminus"`
+}
+
+// TerminusOut (Sub-struct untuk Stasiun Akhir per Arah)
+type TerminusOut struct {
+	Arah        string `json:"arah"` // "LB" atau "HI"
+	IdStasiun   string `json:"id_stasiun"`
+	NamaStasiun string `json:"nama_stasiun"`
+}
+
+// LineDetailOut (Output Lintasan beserta Urutan Stasiun)
+type LineDetailOut struct {
+	LineOut
+	Stasiun []LineStationOut `json:"stasiun"`
+}
+
+// LineStationOut (Sub-struct untuk Stasiun dalam Lintasan, urut dari arah LB ke HI)
+type LineStationOut struct {
+	Urutan            int    `json:"urutan"`
+	ID                string `json:"id"`
+	Nama              string `json:"nama"`
+	WaktuDariAwal     int    `json:"waktu_dari_awal"`     // Menit dari terminus Lebak Bulus
+	WaktuKeBerikutnya *int   `json:"waktu_ke_berikutnya"` // Menit ke stasiun berikutnya arah HI, null di terminus akhir
+}
+
 // DetailStationOut (Output Detail Lengkap Stasiun)
 type DetailStationOut struct {
 	ID                   string                    `json:"id"`
@@ -0,0 +1,75 @@
+package station
+
+import (
+	"sort"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// DefaultServiceDayStart adalah jam mulai hari operasional kalau tidak diatur lewat WithServiceDayStart.
+// Keberangkatan sebelum jam ini (misalnya 00:15) dianggap kereta malam milik hari operasional sebelumnya.
+const DefaultServiceDayStart = 3 * time.Hour
+
+// ServiceDay adalah satu hari operasional MRT.
+// Hari operasional bisa melewati tengah malam: keberangkatan yang jamnya lebih kecil
+// dari Start ditempatkan di tanggal berikutnya, bukan di pagi hari tanggal Date.
+type ServiceDay struct {
+	Date    time.Time     // jam 00:00 tanggal operasional, di zona waktu operator
+	Start   time.Duration // jam mulai hari operasional, dihitung dari tengah malam
+	DayType string        // "biasa" atau "libur"
+}
+
+// Departures mengembalikan semua keberangkatan ke arah destination ("LB"/"HI")
+// pada hari operasional ini, sudah terurut dari yang paling awal dan tanpa duplikat.
+// Entri jadwal yang rusak dilewati; daftarnya bisa dilihat di GET /v1/admin/data-quality.
+func (d ServiceDay) Departures(schedule station.ScheduleIn, destination string) ([]time.Time, error) {
+	raw, err := SelectTimetable(schedule, destination, d.DayType)
+	if err != nil {
+		return nil, err
+	}
+
+	times, _ := ConvertScheduleToTimeFormat(raw, d.Date)
+
+	// Jadwal lewat tengah malam (sebelum jam mulai) pindah ke tanggal berikutnya
+	for i, t := range times {
+		if t.Sub(d.Date) < d.Start {
+			times[i] = t.AddDate(0, 0, 1)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	// "00:30" dan "24:30" bisa jatuh di waktu yang sama setelah dipindah
+	resp := times[:0]
+	for _, t := range times {
+		if len(resp) == 0 || !t.Equal(resp[len(resp)-1]) {
+			resp = append(resp, t)
+		}
+	}
+
+	return resp, nil
+}
+
+// Label mengembalikan tanggal operasional dalam format "2006-01-02".
+func (d ServiceDay) Label() string {
+	return d.Date.Format("2006-01-02")
+}
+
+// ClockTime mengubah jam "HH:MM" menjadi waktu pada hari operasional ini.
+// Jam sebelum Start dianggap lewat tengah malam (tanggal berikutnya), sama seperti Departures.
+func (d ServiceDay) ClockTime(raw string) (time.Time, error) {
+	parsed, err := time.Parse("15:04", raw)
+	if err != nil {
+		return time.Time{}, apperror.InvalidInput(apperror.CodeInvalidTime, "invalid time "+raw+", use HH:MM")
+	}
+
+	t := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, d.Date.Location())
+	if t.Sub(d.Date) < d.Start {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,114 @@
+package station
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// Lintasan MRT Jakarta. Saat ini hanya ada satu lintasan (Utara–Selatan).
+const (
+	LineNorthSouthID   = "north-south"
+	LineNorthSouthName = "Lintas Utara-Selatan"
+)
+
+// Line adalah model lintasan beserta urutan stasiunnya,
+// dimulai dari terminus Lebak Bulus (arah "LB") sampai terminus Bundaran HI (arah "HI").
+type Line struct {
+	ID       string
+	Name     string
+	Stations []LineStation
+}
+
+// LineStation adalah satu stasiun dalam lintasan.
+// MinutesFromStart adalah lama perjalanan dari terminus Lebak Bulus.
+type LineStation struct {
+	ID               string
+	Name             string
+	MinutesFromStart int
+}
+
+// BuildLine menyusun urutan stasiun dari data FareIn.
+// API MRT tidak menyediakan urutan stasiun, jadi urutan diturunkan dari EstimasiIn.Waktu:
+// stasiun diurutkan berdasarkan lama perjalanan dari terminus Lebak Bulus.
+func BuildLine(fares []station.FareIn) (*Line, error) {
+	var terminus *station.FareIn
+	for i, st := range fares {
+		if strings.Contains(strings.ToLower(st.Nama), strings.ToLower(DestinationMap["LB"])) {
+			terminus = &fares[i]
+			break
+		}
+	}
+	if terminus == nil {
+		return nil, apperror.New(apperror.KindUpstreamMalformed, apperror.CodeLineUnavailable, "cannot build line: Lebak Bulus terminus not found")
+	}
+
+	minutes := map[string]int{terminus.ID: 0}
+	for _, e := range terminus.Estimasi {
+		waktu, err := ParseMinutes(e.Waktu)
+		if err != nil {
+			return nil, dataQualityError(terminus.ID, "estimasi.waktu", e.Waktu)
+		}
+		minutes[e.IDStasiunTujuan] = waktu
+	}
+
+	line := &Line{ID: LineNorthSouthID, Name: LineNorthSouthName}
+	for _, st := range fares {
+		waktu, ok := minutes[st.ID]
+		if !ok {
+			return nil, apperror.New(apperror.KindUpstreamMalformed, apperror.CodeLineUnavailable, "cannot build line: travel time from terminus not found for station "+st.ID)
+		}
+		line.Stations = append(line.Stations, LineStation{
+			ID:               st.ID,
+			Name:             st.Nama,
+			MinutesFromStart: waktu,
+		})
+	}
+
+	sort.SliceStable(line.Stations, func(i, j int) bool {
+		return line.Stations[i].MinutesFromStart < line.Stations[j].MinutesFromStart
+	})
+
+	return line, nil
+}
+
+// Index mengembalikan posisi stasiun dalam lintasan (0 = terminus Lebak Bulus).
+func (l *Line) Index(id string) (int, bool) {
+	for i, st := range l.Stations {
+		if st.ID == id {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// Terminus mengembalikan stasiun terakhir untuk arah "LB" atau "HI".
+func (l *Line) Terminus(direction string) LineStation {
+	if direction == "LB" {
+		return l.Stations[0]
+	}
+
+	return l.Stations[len(l.Stations)-1]
+}
+
+// Direction menentukan arah kereta ("LB"/"HI") untuk perjalanan fromId → toId
+// berdasarkan urutan stasiun dalam lintasan.
+func (l *Line) Direction(fromId, toId string) (string, error) {
+	fromIndex, okFrom := l.Index(fromId)
+	toIndex, okTo := l.Index(toId)
+	if !okFrom || !okTo {
+		return "", apperror.NotFound(apperror.CodeStationNotFound, "station not found")
+	}
+	if fromIndex == toIndex {
+		return "", apperror.InvalidInput(apperror.CodeSameStation, "from and to must be different stations")
+	}
+
+	if toIndex > fromIndex {
+		return "HI", nil
+	}
+
+	return "LB", nil
+}
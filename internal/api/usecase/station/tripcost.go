@@ -0,0 +1,209 @@
+package station
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// EstimateTripCost menghitung tarif dan durasi untuk beberapa perjalanan sekaligus.
+//  1. Setiap leg dihitung tarif dasarnya, lalu dipotong sesuai kategori penumpang pada tanggal leg tersebut.
+//  2. Leg dikelompokkan per tanggal, total per tanggal dibatasi oleh batas harian kategori (kalau ada).
+//     Tanggal di luar masa berlaku kategori dihitung dengan tarif umum dan dicatat di TanggalTarifUmum.
+//  3. Kalau query.Projection diisi, semua leg dianggap pola satu hari dan dikalikan ke hari-hari di bulan itu.
+func (u *usecase) EstimateTripCost(ctx context.Context, query TripCostQuery) (*TripCostOut, error) {
+	if len(query.Legs) == 0 {
+		return nil, apperror.InvalidInput(apperror.CodeMissingLegs, "legs is required")
+	}
+	if len(query.Legs) > MaxTripLegs {
+		return nil, apperror.InvalidInput(apperror.CodeTooManyLegs, fmt.Sprintf("too many legs, maximum is %d", MaxTripLegs))
+	}
+
+	var fields []apperror.FieldError
+	for i, leg := range query.Legs {
+		prefix := fmt.Sprintf("legs[%d].", i)
+		fields = append(fields, validateStationPair(prefix+"from", prefix+"to", leg.From, leg.To)...)
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields...)
+	}
+
+	fares, err := u.service.FetchFares(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := u.serviceDay(u.now(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TripCostOut{
+		MataUang:   CurrencyIDR,
+		Perjalanan: make([]TripLegOut, 0, len(query.Legs)),
+	}
+
+	days := map[string]*TripDayOut{}
+	for i, leg := range query.Legs {
+		date := today.Date
+		if leg.Date != "" {
+			if date, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(leg.Date), u.loc); err != nil {
+				return nil, apperror.InvalidInput(apperror.CodeInvalidDate, fmt.Sprintf("leg %d: invalid date %q, use YYYY-MM-DD", i+1, leg.Date))
+			}
+		}
+
+		from, to, estimasi, err := findEstimasi(fares, leg.From, leg.To)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i+1, err)
+		}
+
+		amount, err := ConvertFareAmount(from.ID, estimasi)
+		if err != nil {
+			return nil, err
+		}
+
+		rincian, err := u.applyFareRules(query.RiderType, amount.TarifRupiah, date)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i+1, err)
+		}
+
+		tanggal := date.Format("2006-01-02")
+		resp.KategoriPenumpang = rincian.KategoriPenumpang
+		if rincian.KategoriDiminta != "" {
+			resp.KategoriPenumpang = rincian.KategoriDiminta
+			if !slices.Contains(resp.TanggalTarifUmum, tanggal) {
+				resp.TanggalTarifUmum = append(resp.TanggalTarifUmum, tanggal)
+			}
+		}
+		resp.Perjalanan = append(resp.Perjalanan, TripLegOut{
+			Urutan:        i + 1,
+			IdDari:        from.ID,
+			Dari:          from.Nama,
+			IdKe:          to.ID,
+			Ke:            to.Nama,
+			Tanggal:       tanggal,
+			FareAmountOut: amount,
+			RincianTarif:  rincian,
+		})
+		resp.TotalTarifDasar += rincian.TarifDasar
+		resp.TotalDurasiMenit += amount.DurasiMenit
+
+		day, ok := days[tanggal]
+		if !ok {
+			day = &TripDayOut{Tanggal: tanggal, BatasHarian: rincian.BatasHarian}
+			days[tanggal] = day
+		}
+		day.JumlahPerjalanan++
+		day.Subtotal += rincian.TarifAkhir
+	}
+
+	for _, day := range days {
+		day.TotalRupiah = capDaily(day.Subtotal, day.BatasHarian)
+		resp.TotalRupiah += day.TotalRupiah
+		resp.PerHari = append(resp.PerHari, *day)
+	}
+	sort.Slice(resp.PerHari, func(i, j int) bool {
+		return resp.PerHari[i].Tanggal < resp.PerHari[j].Tanggal
+	})
+	sort.Strings(resp.TanggalTarifUmum)
+
+	resp.TotalDiskon = resp.TotalTarifDasar - resp.TotalRupiah
+	resp.Total = FormatRupiah(resp.TotalRupiah)
+
+	if query.Projection != nil {
+		if resp.Proyeksi, err = u.projectTripCost(fares, query); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// projectTripCost menghitung biaya satu bulan kalau semua leg di query diulang setiap hari
+// yang jenis harinya cocok dengan query.Projection.DayType (kosong = semua hari).
+// Potongan dihitung ulang per tanggal, jadi kategori yang masa berlakunya terbatas tetap akurat:
+// tanggal di luar masa berlaku dihitung dengan tarif umum dan dicatat di TanggalTarifUmum.
+func (u *usecase) projectTripCost(fares []station.FareIn, query TripCostQuery) (*TripProjectionOut, error) {
+	projection := query.Projection
+
+	month, err := time.ParseInLocation("2006-01", strings.TrimSpace(projection.Month), u.loc)
+	if err != nil {
+		return nil, apperror.InvalidInput(apperror.CodeInvalidMonth, fmt.Sprintf("invalid projection month %q, use YYYY-MM", projection.Month))
+	}
+
+	filter := strings.ToLower(projection.DayType)
+	if filter != "" && filter != DayTypeBiasa && filter != DayTypeLibur {
+		return nil, apperror.InvalidInput(apperror.CodeInvalidDayType, "invalid projection day_type, use 'biasa' or 'libur'")
+	}
+
+	// Tarif dasar dan durasi tiap leg tidak bergantung tanggal, cukup dihitung sekali
+	bases := make([]int64, len(query.Legs))
+	minutes := 0
+	for i, leg := range query.Legs {
+		from, _, estimasi, err := findEstimasi(fares, leg.From, leg.To)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i+1, err)
+		}
+		amount, err := ConvertFareAmount(from.ID, estimasi)
+		if err != nil {
+			return nil, err
+		}
+		bases[i] = amount.TarifRupiah
+		minutes += amount.DurasiMenit
+	}
+
+	resp := &TripProjectionOut{
+		Bulan:     month.Format("2006-01"),
+		JenisHari: filter,
+	}
+	if resp.JenisHari == "" {
+		resp.JenisHari = "semua"
+	}
+
+	for date := month; date.Month() == month.Month(); date = date.AddDate(0, 0, 1) {
+		dayType, err := u.dayType(date, "")
+		if err != nil {
+			return nil, err
+		}
+		if filter != "" && dayType != filter {
+			continue
+		}
+
+		var subtotal, dailyCap int64
+		fallback := false
+		for _, base := range bases {
+			rincian, err := u.applyFareRules(query.RiderType, base, date)
+			if err != nil {
+				return nil, fmt.Errorf("projection: %w", err)
+			}
+			subtotal += rincian.TarifAkhir
+			dailyCap = rincian.BatasHarian
+			fallback = fallback || rincian.KategoriDiminta != ""
+		}
+		if fallback {
+			resp.TanggalTarifUmum = append(resp.TanggalTarifUmum, date.Format("2006-01-02"))
+		}
+
+		resp.JumlahHari++
+		resp.TotalRupiah += capDaily(subtotal, dailyCap)
+		resp.TotalDurasiMenit += minutes
+	}
+	resp.Total = FormatRupiah(resp.TotalRupiah)
+
+	return resp, nil
+}
+
+// capDaily membatasi total tarif satu hari sesuai batas harian (0 = tanpa batas).
+func capDaily(subtotal, dailyCap int64) int64 {
+	if dailyCap > 0 && subtotal > dailyCap {
+		return dailyCap
+	}
+
+	return subtotal
+}
@@ -0,0 +1,233 @@
+package station
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/internal/farerule"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// tripFares: Lebak Bulus ↔ Bundaran HI Rp 14.000, Lebak Bulus → Istora Rp 12.000.
+var tripFares = []station.FareIn{
+	{ID: "38", Nama: "Lebak Bulus", Estimasi: []station.EstimasiIn{
+		{IDStasiunTujuan: "21", Tarif: "Rp 14.000", Waktu: "30 menit"},
+		{IDStasiunTujuan: "30", Tarif: "Rp 12.000", Waktu: "25 menit"},
+	}},
+	{ID: "21", Nama: "Bundaran HI", Estimasi: []station.EstimasiIn{
+		{IDStasiunTujuan: "38", Tarif: "Rp 14.000", Waktu: "30 menit"},
+	}},
+	{ID: "30", Nama: "Istora"},
+}
+
+const tripRules = `
+rider_types:
+  - id: pelajar
+    name: Pelajar
+    discount_percent: 50
+    daily_cap: 14000
+  - id: promo
+    name: Promo
+    discount_amount: 1000
+    valid_from: 2026-11-01
+    valid_until: 2026-11-15
+`
+
+func newTripUsecase(t *testing.T) Usecase {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fare_rules.yaml")
+	if err := os.WriteFile(path, []byte(tripRules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules := farerule.New(path)
+	if err := rules.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	return NewUsecase(stubService{fares: tripFares},
+		WithClock(FixedClock(wib("2026-11-02 07:00"))),
+		WithFareRules(rules),
+	)
+}
+
+func TestEstimateTripCost(t *testing.T) {
+	commute := func(date string) []TripLegQuery {
+		return []TripLegQuery{{From: "38", To: "21", Date: date}, {From: "21", To: "38", Date: date}}
+	}
+
+	tests := []struct {
+		name         string
+		riderType    string
+		legs         []TripLegQuery
+		wantTotal    int64
+		wantDays     []int64 // total per tanggal, urut tanggal
+		wantCategory string
+		wantUmum     []string
+		wantCode     string
+	}{
+		{
+			name:         "umum without discount",
+			legs:         commute("2026-11-02"),
+			wantTotal:    28000,
+			wantDays:     []int64{28000},
+			wantCategory: "umum",
+		},
+		{
+			name:         "leg without date uses today",
+			legs:         []TripLegQuery{{From: "38", To: "21"}},
+			wantTotal:    14000,
+			wantDays:     []int64{14000},
+			wantCategory: "umum",
+		},
+		{
+			name:         "percent discount within daily cap",
+			riderType:    "pelajar",
+			legs:         commute("2026-11-02"),
+			wantTotal:    14000,
+			wantDays:     []int64{14000},
+			wantCategory: "pelajar",
+		},
+		{
+			name:         "daily cap applied per date",
+			riderType:    "pelajar",
+			legs:         append(append(commute("2026-11-02"), TripLegQuery{From: "38", To: "30", Date: "2026-11-02"}), commute("2026-11-03")[0]),
+			wantTotal:    14000 + 7000,
+			wantDays:     []int64{14000, 7000},
+			wantCategory: "pelajar",
+		},
+		{
+			name:         "promo within validity",
+			riderType:    "promo",
+			legs:         commute("2026-11-15"),
+			wantTotal:    26000,
+			wantDays:     []int64{26000},
+			wantCategory: "promo",
+		},
+		{
+			name:         "date outside validity falls back to umum",
+			riderType:    "promo",
+			legs:         append(commute("2026-11-16"), commute("2026-11-15")...),
+			wantTotal:    28000 + 26000,
+			wantDays:     []int64{26000, 28000},
+			wantCategory: "promo",
+			wantUmum:     []string{"2026-11-16"},
+		},
+		{name: "unknown rider type", riderType: "vip", legs: commute("2026-11-02"), wantCode: apperror.CodeInvalidRiderType},
+		{name: "unknown station", legs: []TripLegQuery{{From: "38", To: "99"}}, wantCode: apperror.CodeStationNotFound},
+		{name: "no fare between stations", legs: []TripLegQuery{{From: "30", To: "21"}}, wantCode: apperror.CodeFareNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newTripUsecase(t).EstimateTripCost(context.Background(), TripCostQuery{RiderType: tt.riderType, Legs: tt.legs})
+			if tt.wantCode != "" {
+				appErr, ok := apperror.As(err)
+				if !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("EstimateTripCost() error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateTripCost() error = %v", err)
+			}
+
+			if resp.TotalRupiah != tt.wantTotal || resp.TotalDiskon != resp.TotalTarifDasar-tt.wantTotal {
+				t.Errorf("total = %d (diskon %d, dasar %d), want %d", resp.TotalRupiah, resp.TotalDiskon, resp.TotalTarifDasar, tt.wantTotal)
+			}
+			days := make([]int64, 0, len(resp.PerHari))
+			for _, day := range resp.PerHari {
+				days = append(days, day.TotalRupiah)
+			}
+			if !slices.Equal(days, tt.wantDays) {
+				t.Errorf("per_hari totals = %v, want %v", days, tt.wantDays)
+			}
+			if resp.KategoriPenumpang != tt.wantCategory || !slices.Equal(resp.TanggalTarifUmum, tt.wantUmum) {
+				t.Errorf("kategori = %q, tanggal_tarif_umum = %v, want %q, %v", resp.KategoriPenumpang, resp.TanggalTarifUmum, tt.wantCategory, tt.wantUmum)
+			}
+
+			// Leg yang jatuh di tanggal tarif umum harus menyebut kategori yang diminta
+			for _, leg := range resp.Perjalanan {
+				fallback := slices.Contains(tt.wantUmum, leg.Tanggal)
+				if fallback && (leg.RincianTarif.KategoriPenumpang != farerule.DefaultRiderType || leg.RincianTarif.KategoriDiminta != tt.riderType) {
+					t.Errorf("leg %d rincian = %+v, want umum requested as %s", leg.Urutan, leg.RincianTarif, tt.riderType)
+				}
+				if !fallback && leg.RincianTarif.KategoriDiminta != "" {
+					t.Errorf("leg %d kategori_diminta = %q, want empty", leg.Urutan, leg.RincianTarif.KategoriDiminta)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateTripCostProjection(t *testing.T) {
+	tests := []struct {
+		name       string
+		riderType  string
+		legs       []TripLegQuery
+		projection TripProjectionQuery
+		wantDays   int
+		wantTotal  int64
+		wantUmum   int
+		firstUmum  string
+		wantCode   string
+	}{
+		{
+			// November 2026 punya 21 hari kerja: 2-13 (10 hari) masih promo, 16-30 (11 hari) tarif umum
+			name:       "promo ending mid month",
+			riderType:  "promo",
+			legs:       []TripLegQuery{{From: "38", To: "21"}, {From: "21", To: "38"}},
+			projection: TripProjectionQuery{Month: "2026-11", DayType: "biasa"},
+			wantDays:   21,
+			wantTotal:  10*26000 + 11*28000,
+			wantUmum:   11,
+			firstUmum:  "2026-11-16",
+		},
+		{
+			// 9 hari akhir pekan, subtotal 7.000 + 7.000 + 6.000 dibatasi 14.000 per hari
+			name:       "daily cap on every projected day",
+			riderType:  "pelajar",
+			legs:       []TripLegQuery{{From: "38", To: "21"}, {From: "21", To: "38"}, {From: "38", To: "30"}},
+			projection: TripProjectionQuery{Month: "2026-11", DayType: "libur"},
+			wantDays:   9,
+			wantTotal:  9 * 14000,
+		},
+		{
+			name:       "all days",
+			legs:       []TripLegQuery{{From: "38", To: "21"}},
+			projection: TripProjectionQuery{Month: "2026-02"},
+			wantDays:   28,
+			wantTotal:  28 * 14000,
+		},
+		{name: "invalid month", legs: []TripLegQuery{{From: "38", To: "21"}}, projection: TripProjectionQuery{Month: "2026-13"}, wantCode: apperror.CodeInvalidMonth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := TripCostQuery{RiderType: tt.riderType, Legs: tt.legs, Projection: &tt.projection}
+			resp, err := newTripUsecase(t).EstimateTripCost(context.Background(), query)
+			if tt.wantCode != "" {
+				appErr, ok := apperror.As(err)
+				if !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("EstimateTripCost() error = %v, want %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateTripCost() error = %v", err)
+			}
+
+			got := resp.Proyeksi
+			if got == nil {
+				t.Fatal("proyeksi = nil")
+			}
+			if got.JumlahHari != tt.wantDays || got.TotalRupiah != tt.wantTotal {
+				t.Errorf("proyeksi = %d days, total %d; want %d days, total %d", got.JumlahHari, got.TotalRupiah, tt.wantDays, tt.wantTotal)
+			}
+			if len(got.TanggalTarifUmum) != tt.wantUmum || (tt.wantUmum > 0 && got.TanggalTarifUmum[0] != tt.firstUmum) {
+				t.Errorf("tanggal_tarif_umum = %v, want %d dates starting %s", got.TanggalTarifUmum, tt.wantUmum, tt.firstUmum)
+			}
+		})
+	}
+}
@@ -1,32 +1,173 @@
 package station
 
 import (
-	"errors"
+	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/IkrmMrbsy/mrt-schedules/internal/api/service/station"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
 	"github.com/IkrmMrbsy/mrt-schedules/pkg/utils"
 )
 
 type Usecase interface {
-	GetAllStation(name string) ([]StationOut, error)
-	CheckScheduleByStation(id string) ([]ScheduleOut, error)
-	GetFareAndDuration(fromId, toId string) (FareOut, error)
-	GetNextTrainByStation(id, destination string) (*NextTrainOut, error)
-	GetStationDetails(id string) (*DetailStationOut, error)
+	GetAllStation(ctx context.Context, name string) ([]StationOut, error)
+	CheckScheduleByStation(ctx context.Context, query ScheduleQuery) ([]ScheduleOut, error)
+	GetTimetableByStation(ctx context.Context, query TimetableQuery) (*TimetableOut, error)
+	GetFareAndDuration(ctx context.Context, query FareQuery) (FareOut, error)
+	GetFareMatrix(ctx context.Context, query FareMatrixQuery) (*FareMatrixOut, error)
+	EstimateTripCost(ctx context.Context, query TripCostQuery) (*TripCostOut, error)
+	GetNextTrainByStation(ctx context.Context, query NextTrainQuery) (*NextTrainOut, error)
+	GetStationDetails(ctx context.Context, id string) (*DetailStationOut, error)
+	PlanJourney(ctx context.Context, query JourneyQuery) (*JourneyOut, error)
+	GetLines(ctx context.Context) ([]LineOut, error)
+	GetLineStations(ctx context.Context, id string) (*LineDetailOut, error)
 }
 
 type usecase struct {
-	service station.Service
+	service  station.Service
+	loc      *time.Location
+	clock    Clock
+	holidays HolidayCalendar
+	dayStart time.Duration
+	rules    FareRules
 }
 
-func NewUsecase(service station.Service) Usecase {
-	return &usecase{service: service}
+// HolidayCalendar dipakai untuk mengecek hari libur nasional.
+// Pada hari libur, jadwal yang dipakai adalah tabel "Libur".
+type HolidayCalendar interface {
+	IsHoliday(t time.Time) bool
 }
 
-func (u *usecase) GetAllStation(name string) ([]StationOut, error) {
-	stations, err := u.service.FetchStations()
+// Option dipakai untuk mengatur perilaku tambahan usecase saat dibuat.
+type Option func(*usecase)
+
+// WithLocation mengatur zona waktu operator. Semua parsing jadwal,
+// perbandingan "sekarang" dan penentuan hari kerja/libur memakai zona ini.
+func WithLocation(loc *time.Location) Option {
+	return func(u *usecase) {
+		if loc != nil {
+			u.loc = loc
+		}
+	}
+}
+
+// WithClock mengganti sumber waktu "sekarang" (default jam sistem).
+func WithClock(clock Clock) Option {
+	return func(u *usecase) {
+		if clock != nil {
+			u.clock = clock
+		}
+	}
+}
+
+// WithHolidayCalendar mengaktifkan pengecekan hari libur nasional.
+func WithHolidayCalendar(holidays HolidayCalendar) Option {
+	return func(u *usecase) {
+		u.holidays = holidays
+	}
+}
+
+// WithFareRules mengaktifkan aturan potongan tarif per kategori penumpang.
+func WithFareRules(rules FareRules) Option {
+	return func(u *usecase) {
+		u.rules = rules
+	}
+}
+
+// WithServiceDayStart mengatur jam mulai hari operasional (dihitung dari tengah malam).
+// Keberangkatan sebelum jam ini dianggap bagian dari hari operasional sebelumnya.
+func WithServiceDayStart(start time.Duration) Option {
+	return func(u *usecase) {
+		if start >= 0 && start < 24*time.Hour {
+			u.dayStart = start
+		}
+	}
+}
+
+func NewUsecase(service station.Service, opts ...Option) Usecase {
+	u := &usecase{
+		service:  service,
+		loc:      DefaultLocation,
+		clock:    systemClock{},
+		dayStart: DefaultServiceDayStart,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// now mengembalikan waktu sekarang di zona waktu operator.
+func (u *usecase) now() time.Time {
+	return u.clock.Now().In(u.loc)
+}
+
+// evaluationTime menentukan waktu acuan perhitungan jadwal:
+// nilai ?at= kalau diisi, selain itu waktu sekarang.
+func (u *usecase) evaluationTime(at string) (time.Time, error) {
+	now := u.now()
+	if at == "" {
+		return now, nil
+	}
+
+	return ParseAt(at, now)
+}
+
+// dayType menentukan jenis hari untuk tanggal t.
+// override ("biasa"/"libur") dipakai kalau diisi, selain itu
+// Sabtu, Minggu dan hari libur nasional dianggap "libur".
+func (u *usecase) dayType(t time.Time, override string) (string, error) {
+	switch strings.ToLower(override) {
+	case DayTypeBiasa:
+		return DayTypeBiasa, nil
+	case DayTypeLibur:
+		return DayTypeLibur, nil
+	case "":
+	default:
+		return "", apperror.InvalidInput(apperror.CodeInvalidDayType, "invalid day_type, use 'biasa' or 'libur'")
+	}
+
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return DayTypeLibur, nil
+	}
+	if u.holidays != nil && u.holidays.IsHoliday(t) {
+		return DayTypeLibur, nil
+	}
+
+	return DayTypeBiasa, nil
+}
+
+// serviceDay menentukan hari operasional yang sedang berjalan pada waktu t.
+// Sebelum jam mulai hari operasional, t masih dihitung milik tanggal sebelumnya.
+// override ("biasa"/"libur") dipakai untuk memaksa jenis hari.
+func (u *usecase) serviceDay(t time.Time, override string) (ServiceDay, error) {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if t.Sub(date) < u.dayStart {
+		date = date.AddDate(0, 0, -1)
+	}
+
+	dayType, err := u.dayType(date, override)
+	if err != nil {
+		return ServiceDay{}, err
+	}
+
+	return ServiceDay{Date: date, Start: u.dayStart, DayType: dayType}, nil
+}
+
+// nextServiceDay mengembalikan hari operasional setelah day.
+// Jenis harinya selalu ditentukan dari kalender (override tidak ikut terbawa).
+func (u *usecase) nextServiceDay(day ServiceDay) ServiceDay {
+	date := day.Date.AddDate(0, 0, 1)
+	dayType, _ := u.dayType(date, "")
+
+	return ServiceDay{Date: date, Start: day.Start, DayType: dayType}
+}
+
+func (u *usecase) GetAllStation(ctx context.Context, name string) ([]StationOut, error) {
+	stations, err := u.service.FetchStations(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -48,131 +189,262 @@ func (u *usecase) GetAllStation(name string) ([]StationOut, error) {
 	return resp, nil
 }
 
-func (u *usecase) CheckScheduleByStation(id string) ([]ScheduleOut, error) {
-	schedules, err := u.service.FetchSchedules()
+// CheckScheduleByStation mengembalikan sisa keberangkatan hari ini untuk kedua arah
+// dalam satu list terurut. Untuk tampilan per arah, pakai GetTimetableByStation.
+func (u *usecase) CheckScheduleByStation(ctx context.Context, query ScheduleQuery) ([]ScheduleOut, error) {
+	at, err := u.evaluationTime(query.At)
 	if err != nil {
 		return nil, err
 	}
 
-	var scheduleSelected station.ScheduleIn
+	day, err := u.serviceDay(at, query.DayType)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleSelected, err := u.scheduleByStation(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConvertDataToResponse(scheduleSelected, day, at)
+}
+
+// GetTimetableByStation mengembalikan jadwal keberangkatan stasiun yang dikelompokkan per arah.
+// Mode "remaining" hanya berisi keberangkatan setelah waktu acuan, mode "full" berisi jadwal sehari penuh.
+func (u *usecase) GetTimetableByStation(ctx context.Context, query TimetableQuery) (*TimetableOut, error) {
+	at, err := u.evaluationTime(query.At)
+	if err != nil {
+		return nil, err
+	}
+
+	day, err := u.serviceDay(at, query.DayType)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := strings.ToLower(query.Mode)
+	if mode == "" {
+		mode = TimetableModeRemaining
+	}
+	if mode != TimetableModeRemaining && mode != TimetableModeFull {
+		return nil, apperror.InvalidInput(apperror.CodeInvalidMode, "invalid mode, use 'remaining' or 'full'")
+	}
+
+	scheduleSelected, err := u.scheduleByStation(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TimetableOut{
+		IdStasiun:   scheduleSelected.IDStasiun,
+		NamaStasiun: scheduleSelected.NamaStasiun,
+		Tanggal:     day.Label(),
+		JenisHari:   day.DayType,
+		Mode:        mode,
+	}
+	for _, destination := range Directions {
+		departures, err := ConvertDirectionSchedule(scheduleSelected, destination, day, at, mode == TimetableModeRemaining)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Arah = append(resp.Arah, DirectionTimetable{
+			Kode:          destination,
+			Tujuan:        DestinationMap[destination],
+			Keberangkatan: departures,
+		})
+	}
+
+	return resp, nil
+}
+
+// scheduleByStation mencari jadwal mentah milik stasiun dengan id tertentu.
+func (u *usecase) scheduleByStation(ctx context.Context, id string) (station.ScheduleIn, error) {
+	schedules, err := u.service.FetchSchedules(ctx)
+	if err != nil {
+		return station.ScheduleIn{}, err
+	}
+
 	for _, item := range schedules {
 		if item.IDStasiun == id {
-			scheduleSelected = item
-			break
+			return item, nil
 		}
 	}
-	if scheduleSelected.IDStasiun == "" {
-		return nil, errors.New("station not found")
-	}
 
-	return ConvertDataToResponse(scheduleSelected)
+	return station.ScheduleIn{}, apperror.NotFound(apperror.CodeStationNotFound, "station not found")
 }
 
-func (u *usecase) GetFareAndDuration(fromId, toId string) (FareOut, error) {
-	stations, err := u.service.FetchFares()
+func (u *usecase) GetFareAndDuration(ctx context.Context, query FareQuery) (FareOut, error) {
+	if fields := validateStationPair("from", "to", query.From, query.To); len(fields) > 0 {
+		return FareOut{}, apperror.Validation(fields...)
+	}
+
+	stations, err := u.service.FetchFares(ctx)
 	if err != nil {
 		return FareOut{}, err
 	}
 
-	var fromName, toName, fare, duration string
-	for _, st := range stations {
-		if st.ID == fromId {
-			fromName = st.Nama
-			for _, e := range st.Estimasi {
-				if e.IDStasiunTujuan == toId {
-					fare = e.Tarif
-					duration = e.Waktu + " menit"
-					break
-				}
-			}
-		}
-		if st.ID == toId {
-			toName = st.Nama
-		}
-		if fromName != "" && toName != "" && fare != "" {
-			break
-		}
+	from, to, estimasi, err := findEstimasi(stations, query.From, query.To)
+	if err != nil {
+		return FareOut{}, err
 	}
 
-	if fromName == "" || toName == "" {
-		return FareOut{}, errors.New("station not found")
+	amount, err := ConvertFareAmount(from.ID, estimasi)
+	if err != nil {
+		return FareOut{}, err
 	}
-	if fare == "" {
-		return FareOut{}, errors.New("fare/estimasi not found between stations")
+
+	rincian, err := u.applyFareRules(query.RiderType, amount.TarifRupiah, u.now())
+	if err != nil {
+		return FareOut{}, err
 	}
 
 	return FareOut{
-		Dari:   fromName,
-		Ke:     toName,
-		Tarif:  fare,
-		Durasi: duration,
+		Dari:          from.Nama,
+		Ke:            to.Nama,
+		FareAmountOut: amount,
+		RincianTarif:  rincian,
 	}, nil
 }
 
-func (u *usecase) GetNextTrainByStation(id, destination string) (*NextTrainOut, error) {
-	schedules, err := u.service.FetchSchedules()
+func (u *usecase) GetNextTrainByStation(ctx context.Context, query NextTrainQuery) (*NextTrainOut, error) {
+	now, err := u.evaluationTime(query.At)
 	if err != nil {
 		return nil, err
 	}
 
-	var scheduleSelected station.ScheduleIn
-	for _, item := range schedules {
-		if item.IDStasiun == id {
-			scheduleSelected = item
-			break
-		}
-	}
-	if scheduleSelected.IDStasiun == "" {
-		return nil, errors.New("station not found")
+	day, err := u.serviceDay(now, query.DayType)
+	if err != nil {
+		return nil, err
 	}
 
-	isWeekend := time.Now().Weekday() == time.Saturday || time.Now().Weekday() == time.Sunday
-	var times []time.Time
+	// Parameter dicek dulu, supaya request yang tidak valid tidak perlu memanggil API MRT
+	limit, after, until, err := nextTrainWindow(query, day, now)
+	if err != nil {
+		return nil, err
+	}
 
-	if destination == "LB" {
-		if isWeekend {
-			times, err = ConvertScheduleToTimeFormat(scheduleSelected.JadwalLebakBulusLibur)
-		} else {
-			times, err = ConvertScheduleToTimeFormat(scheduleSelected.JadwalLebakBulusBiasa)
-		}
-	} else if destination == "HI" {
-		if isWeekend {
-			times, err = ConvertScheduleToTimeFormat(scheduleSelected.JadwalBundaranHILibur)
-		} else {
-			times, err = ConvertScheduleToTimeFormat(scheduleSelected.JadwalBundaranHIBiasa)
-		}
-	} else {
-		return nil, errors.New("invalid destination, use 'LB' or 'HI'")
+	scheduleSelected, err := u.scheduleByStation(ctx, query.ID)
+	if err != nil {
+		return nil, err
 	}
+
+	departures, err := u.upcomingDepartures(scheduleSelected, query.Destination, day, after, until, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
 	var nextTrains []TrainSchedule
-	for _, t := range times {
-		if t.After(now) {
-			nextTrains = append(nextTrains, TrainSchedule{WaktuKeberangkatan: t.Format("15:04")})
-			if len(nextTrains) == 3 {
-				break
-			}
-		}
+	for _, d := range departures {
+		nextTrains = append(nextTrains, ConvertTrainSchedule(d.time, now, d.dayType))
 	}
 
 	if len(nextTrains) == 0 {
-		return nil, errors.New("no next train available today")
+		return nil, apperror.NoService(apperror.CodeNoNextTrain, "no next train available")
 	}
 
 	return &NextTrainOut{
-		IdKereta:         id,
+		IdKereta:         query.ID,
 		Stasiun:          scheduleSelected.NamaStasiun,
-		Tujuan:           DestinationMap[destination],
+		Tujuan:           DestinationMap[query.Destination],
+		JenisHari:        day.DayType,
+		WaktuAcuan:       now.Format(time.RFC3339),
 		KeretaBerikutnya: nextTrains,
 	}, nil
 }
 
-func (u *usecase) GetStationDetails(id string) (*DetailStationOut, error) {
-	stations, err := u.service.FetchStations()
+// departure adalah satu keberangkatan beserta jenis hari tabel jadwal asalnya.
+type departure struct {
+	time    time.Time
+	dayType string
+}
+
+// upcomingDepartures mencari maksimal limit keberangkatan ke arah destination yang berangkat
+// setelah after (dan tidak lewat dari until kalau diisi). Pencarian dimulai dari hari operasional day,
+// kalau belum cukup lanjut ke hari operasional berikutnya.
+func (u *usecase) upcomingDepartures(schedule station.ScheduleIn, destination string, day ServiceDay, after, until time.Time, limit int) ([]departure, error) {
+	var resp []departure
+	current := day
+	for i := 0; i < 2 && len(resp) < limit; i++ {
+		times, err := current.Departures(schedule, destination)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range times {
+			if !t.After(after) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				break
+			}
+			resp = append(resp, departure{time: t, dayType: current.DayType})
+			if len(resp) == limit {
+				break
+			}
+		}
+
+		current = u.nextServiceDay(current)
+	}
+
+	return resp, nil
+}
+
+// nextTrainWindow memvalidasi parameter limit/from/until dan mengembalikan:
+// - limit → jumlah maksimal kereta.
+// - after → hanya kereta yang berangkat setelah waktu ini (now atau from, mana yang lebih akhir).
+// - until → batas akhir keberangkatan (zero value kalau tidak dibatasi).
+//
+// Urutan from/until dicek di sini (bukan di handler) karena jam sebelum awal hari operasional
+// milik hari yang sama, jadi from=23:00 dan until=00:30 tetap valid; kesalahannya dilaporkan sebagai field until.
+// Urutan dicek dengan nilai from apa adanya. Setelah itu jendela dipotong ke now,
+// karena next-train tidak pernah mengembalikan kereta yang sudah berangkat: jendela yang sudah
+// lewat seluruhnya menghasilkan daftar kosong, dan jendela yang sudah dimulai hanya berisi sisa keretanya.
+func nextTrainWindow(query NextTrainQuery, day ServiceDay, now time.Time) (limit int, after, until time.Time, err error) {
+	windowed := query.From != "" || query.Until != ""
+
+	switch {
+	case query.Limit != nil:
+		limit = *query.Limit
+	case windowed:
+		limit = MaxNextTrainLimit
+	default:
+		limit = DefaultNextTrainLimit
+	}
+	if limit < 1 || limit > MaxNextTrainLimit {
+		err = apperror.InvalidInput(apperror.CodeInvalidLimit, fmt.Sprintf("invalid limit, must be between 1 and %d", MaxNextTrainLimit))
+		return
+	}
+
+	var from time.Time
+	if query.From != "" {
+		if from, err = day.ClockTime(query.From); err != nil {
+			return
+		}
+	}
+
+	if query.Until != "" {
+		if until, err = day.ClockTime(query.Until); err != nil {
+			return
+		}
+		if query.From != "" && !until.After(from) {
+			err = apperror.Validation(apperror.FieldError{Field: "until", Code: apperror.CodeInvalidTimeWindow, Message: "until must be after from"})
+			return
+		}
+	}
+
+	after = now
+	if query.From != "" && from.After(now) {
+		// Kereta yang berangkat tepat pada jam "from" ikut dihitung
+		after = from.Add(-time.Nanosecond)
+	}
+
+	return
+}
+
+func (u *usecase) GetStationDetails(ctx context.Context, id string) (*DetailStationOut, error) {
+	stations, err := u.service.FetchStations(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +458,7 @@ func (u *usecase) GetStationDetails(id string) (*DetailStationOut, error) {
 	}
 
 	if stationData == nil {
-		return nil, errors.New("station not found")
+		return nil, apperror.NotFound(apperror.CodeStationNotFound, "station not found")
 	}
 
 	antarmodaParsed := ParseAntarmoda(stationData.Antarmoda)
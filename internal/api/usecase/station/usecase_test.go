@@ -0,0 +1,120 @@
+package station
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestNextTrainWindow(t *testing.T) {
+	day := ServiceDay{Date: wib("2026-10-19 00:00"), Start: DefaultServiceDayStart, DayType: DayTypeBiasa}
+	now := wib("2026-10-19 17:30")
+
+	tests := []struct {
+		name      string
+		query     NextTrainQuery
+		wantLimit int
+		wantAfter time.Time // kereta tepat pada jam ini ikut dihitung
+		wantUntil time.Time
+		wantCode  string
+		wantField string // diisi kalau error dilaporkan per field
+	}{
+		{
+			name:      "no window uses default limit",
+			query:     NextTrainQuery{},
+			wantLimit: DefaultNextTrainLimit,
+			wantAfter: now,
+		},
+		{
+			name:      "window uses max limit",
+			query:     NextTrainQuery{From: "18:00", Until: "19:00"},
+			wantLimit: MaxNextTrainLimit,
+			wantAfter: wib("2026-10-19 18:00"),
+			wantUntil: wib("2026-10-19 19:00"),
+		},
+		{
+			name:      "explicit limit kept",
+			query:     NextTrainQuery{Limit: intPtr(5), From: "18:00"},
+			wantLimit: 5,
+			wantAfter: wib("2026-10-19 18:00"),
+		},
+		{
+			name:      "window already started is clamped to now",
+			query:     NextTrainQuery{From: "17:00", Until: "18:00"},
+			wantLimit: MaxNextTrainLimit,
+			wantAfter: now,
+			wantUntil: wib("2026-10-19 18:00"),
+		},
+		{
+			name:      "window wholly in the past is not an ordering error",
+			query:     NextTrainQuery{From: "07:00", Until: "08:00"},
+			wantLimit: MaxNextTrainLimit,
+			wantAfter: now,
+			wantUntil: wib("2026-10-19 08:00"),
+		},
+		{
+			name:      "window across midnight",
+			query:     NextTrainQuery{From: "23:00", Until: "00:30"},
+			wantLimit: MaxNextTrainLimit,
+			wantAfter: wib("2026-10-19 23:00"),
+			wantUntil: wib("2026-10-20 00:30"),
+		},
+		{
+			name:      "until only",
+			query:     NextTrainQuery{Until: "19:00"},
+			wantLimit: MaxNextTrainLimit,
+			wantAfter: now,
+			wantUntil: wib("2026-10-19 19:00"),
+		},
+		{name: "until before from", query: NextTrainQuery{From: "19:00", Until: "18:00"}, wantCode: apperror.CodeInvalidTimeWindow, wantField: "until"},
+		{name: "until equals from", query: NextTrainQuery{From: "18:00", Until: "18:00"}, wantCode: apperror.CodeInvalidTimeWindow, wantField: "until"},
+		{name: "until before from in the past", query: NextTrainQuery{From: "08:00", Until: "07:00"}, wantCode: apperror.CodeInvalidTimeWindow, wantField: "until"},
+		{name: "limit zero", query: NextTrainQuery{Limit: intPtr(0)}, wantCode: apperror.CodeInvalidLimit},
+		{name: "limit zero with window", query: NextTrainQuery{Limit: intPtr(0), From: "18:00"}, wantCode: apperror.CodeInvalidLimit},
+		{name: "limit too small", query: NextTrainQuery{Limit: intPtr(-1)}, wantCode: apperror.CodeInvalidLimit},
+		{name: "limit too large", query: NextTrainQuery{Limit: intPtr(MaxNextTrainLimit + 1)}, wantCode: apperror.CodeInvalidLimit},
+		{name: "bad from", query: NextTrainQuery{From: "6pm"}, wantCode: apperror.CodeInvalidTime},
+		{name: "bad until", query: NextTrainQuery{Until: "25:00"}, wantCode: apperror.CodeInvalidTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, after, until, err := nextTrainWindow(tt.query, day, now)
+			if tt.wantCode != "" {
+				appErr, ok := apperror.As(err)
+				if !ok || appErr.Kind != apperror.KindInvalidInput {
+					t.Fatalf("nextTrainWindow() error = %v, want invalid input", err)
+				}
+				if tt.wantField == "" {
+					if appErr.Code != tt.wantCode {
+						t.Errorf("code = %s, want %s", appErr.Code, tt.wantCode)
+					}
+					return
+				}
+				if appErr.Code != apperror.CodeValidationFailed || len(appErr.Fields) != 1 ||
+					appErr.Fields[0].Field != tt.wantField || appErr.Fields[0].Code != tt.wantCode {
+					t.Errorf("error = %+v, want %s on field %s", appErr, tt.wantCode, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("nextTrainWindow() error = %v", err)
+			}
+
+			if limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", limit, tt.wantLimit)
+			}
+			// after eksklusif: kereta pada wantAfter harus lolos, kereta 1ns sebelumnya tidak
+			if after.After(tt.wantAfter) || after.Before(tt.wantAfter.Add(-time.Nanosecond)) {
+				t.Errorf("after = %v, want just before or at %v", after, tt.wantAfter)
+			}
+			if !until.Equal(tt.wantUntil) {
+				t.Errorf("until = %v, want %v", until, tt.wantUntil)
+			}
+		})
+	}
+}
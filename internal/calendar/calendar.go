@@ -0,0 +1,113 @@
+package calendar
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Holiday adalah satu hari libur nasional.
+// Tanggal ditulis dalam format "2006-01-02".
+type Holiday struct {
+	Date string `yaml:"date" json:"tanggal"`
+	Name string `yaml:"name" json:"nama"`
+}
+
+// file adalah struktur file YAML kalender libur, contoh:
+//
+//	holidays:
+//	  - date: 2026-08-17
+//	    name: Hari Kemerdekaan Republik Indonesia
+type file struct {
+	Holidays []Holiday `yaml:"holidays"`
+}
+
+// Calendar menyimpan daftar hari libur nasional yang dibaca dari file YAML.
+// Aman dipakai bersamaan oleh banyak goroutine dan bisa di-reload saat runtime.
+type Calendar struct {
+	path string
+
+	mu       sync.RWMutex
+	holidays map[string]Holiday
+	loadedAt time.Time
+}
+
+// New membuat Calendar kosong yang akan membaca file di path saat Reload dipanggil.
+func New(path string) *Calendar {
+	return &Calendar{
+		path:     path,
+		holidays: map[string]Holiday{},
+	}
+}
+
+// Reload membaca ulang file kalender. Kalau file gagal dibaca atau formatnya salah,
+// data lama tetap dipakai dan error dikembalikan.
+func (c *Calendar) Reload() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+
+	var parsed file
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+
+	holidays := make(map[string]Holiday, len(parsed.Holidays))
+	for _, h := range parsed.Holidays {
+		date, err := time.Parse("2006-01-02", h.Date)
+		if err != nil {
+			return err
+		}
+		h.Date = date.Format("2006-01-02")
+		holidays[h.Date] = h
+	}
+
+	c.mu.Lock()
+	c.holidays = holidays
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// IsHoliday mengecek apakah tanggal t (di zona waktu t) adalah hari libur nasional.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.holidays[t.Format("2006-01-02")]
+	return ok
+}
+
+// List mengembalikan semua hari libur, diurutkan berdasarkan tanggal.
+func (c *Calendar) List() []Holiday {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]Holiday, 0, len(c.holidays))
+	for _, h := range c.holidays {
+		list = append(list, h)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Date < list[j].Date
+	})
+
+	return list
+}
+
+// LoadedAt mengembalikan waktu terakhir file kalender berhasil dibaca.
+func (c *Calendar) LoadedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.loadedAt
+}
+
+// Path mengembalikan lokasi file kalender.
+func (c *Calendar) Path() string {
+	return c.path
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +14,53 @@ type config struct {
 	ServerPort  string
 	HttpTimeout time.Duration
 	MRTApiURL   string
+
+	// URL upstream per resource (default MRTApiURL)
+	MRTStationsURL  string
+	MRTSchedulesURL string
+	MRTFaresURL     string
+
+	// Lama payload upstream dipakai ulang oleh resource lain yang berbagi URL
+	UpstreamReuseWindow time.Duration
+
+	// Retry request ke upstream
+	RetryMaxAttempts int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	RetryJitter      float64
+	RetryStatuses    []int
+
+	// Circuit breaker upstream
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+
+	// Batas waktu total satu request API (0 = tanpa batas)
+	RequestTimeout time.Duration
+
+	// TTL cache per method service station
+	CacheTTLStations  time.Duration
+	CacheTTLSchedules time.Duration
+	CacheTTLFares     time.Duration
+	CacheStaleWindow  time.Duration
+
+	// Snapshot payload upstream di disk
+	SnapshotDir string
+	OfflineMode bool
+
+	// Zona waktu operator MRT (default Asia/Jakarta / WIB)
+	Location *time.Location
+
+	// File YAML kalender hari libur nasional
+	HolidayFile string
+
+	// File YAML aturan tarif per kategori penumpang
+	FareRulesFile string
+
+	// Jam mulai hari operasional; keberangkatan sebelum jam ini milik hari sebelumnya
+	ServiceDayStart time.Duration
+
+	// Token Bearer untuk endpoint /v1/admin (kosong = tanpa autentikasi)
+	AdminToken string
 }
 
 func LoadConfig() *config {
@@ -25,9 +73,174 @@ func LoadConfig() *config {
 		timeout = 10
 	}
 
+	// MRT_API_URL=offline sama artinya dengan OFFLINE_MODE=true
+	apiURL := os.Getenv("MRT_API_URL")
+	offline, _ := strconv.ParseBool(os.Getenv("OFFLINE_MODE"))
+	if strings.EqualFold(apiURL, "offline") {
+		offline = true
+	}
+
+	snapshotDir := os.Getenv("SNAPSHOT_DIR")
+	if snapshotDir == "" {
+		snapshotDir = "data/snapshots"
+	}
+
+	holidayFile := os.Getenv("HOLIDAY_FILE")
+	if holidayFile == "" {
+		holidayFile = "holidays.yaml"
+	}
+
+	fareRulesFile := os.Getenv("FARE_RULES_FILE")
+	if fareRulesFile == "" {
+		fareRulesFile = "fare_rules.yaml"
+	}
+
 	return &config{
 		ServerPort:  os.Getenv("SERVER_PORT"),
 		HttpTimeout: time.Duration(timeout) * time.Second,
-		MRTApiURL:   os.Getenv("MRT_API_URL"),
+		MRTApiURL:   apiURL,
+
+		MRTStationsURL:      os.Getenv("MRT_STATIONS_URL"),
+		MRTSchedulesURL:     os.Getenv("MRT_SCHEDULES_URL"),
+		MRTFaresURL:         os.Getenv("MRT_FARES_URL"),
+		UpstreamReuseWindow: secondsFromEnv("UPSTREAM_REUSE_WINDOW", 30),
+
+		RetryMaxAttempts: intFromEnv("RETRY_MAX_ATTEMPTS", 3),
+		RetryBaseDelay:   millisFromEnv("RETRY_BASE_DELAY_MS", 200),
+		RetryMaxDelay:    millisFromEnv("RETRY_MAX_DELAY_MS", 2000),
+		RetryJitter:      floatFromEnv("RETRY_JITTER", 0.2),
+		RetryStatuses:    statusesFromEnv("RETRY_STATUSES", []int{429, 500, 502, 503, 504}),
+
+		BreakerFailureThreshold: intFromEnv("BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeout:      secondsFromEnv("BREAKER_OPEN_TIMEOUT", 30),
+
+		RequestTimeout: secondsFromEnv("REQUEST_TIMEOUT", 15),
+
+		CacheTTLStations:  secondsFromEnv("CACHE_TTL_STATIONS", 300),
+		CacheTTLSchedules: secondsFromEnv("CACHE_TTL_SCHEDULES", 60),
+		CacheTTLFares:     secondsFromEnv("CACHE_TTL_FARES", 3600),
+		CacheStaleWindow:  secondsFromEnv("CACHE_STALE_WINDOW", 600),
+
+		SnapshotDir: snapshotDir,
+		OfflineMode: offline,
+
+		Location:    loadLocation(os.Getenv("TIMEZONE")),
+		HolidayFile: holidayFile,
+
+		FareRulesFile: fareRulesFile,
+
+		ServiceDayStart: clockFromEnv("SERVICE_DAY_START", 3*time.Hour),
+
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
+
+// clockFromEnv membaca env berisi jam "HH:MM" dan mengembalikannya sebagai durasi dari tengah malam.
+func clockFromEnv(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
 	}
+
+	t, err := time.Parse("15:04", raw)
+	if err != nil {
+		log.Printf("Invalid %s=%q, expected HH:MM", key, raw)
+		return fallback
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// loadLocation memuat zona waktu dari nama IANA (contoh: "Asia/Jakarta").
+// Kalau kosong atau tidak dikenali, fallback ke WIB (UTC+7).
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Invalid TIMEZONE=%q, fallback to WIB (UTC+7): %v", name, err)
+		return time.FixedZone("WIB", 7*60*60)
+	}
+
+	return loc
+}
+
+// secondsFromEnv membaca env berisi jumlah detik.
+// Kalau env tidak di-set atau tidak valid, pakai nilai fallback.
+// Nilai 0 tetap dihormati (misalnya untuk mematikan cache).
+func secondsFromEnv(key string, fallback int) time.Duration {
+	seconds := fallback
+	if raw, ok := os.LookupEnv(key); ok {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid %s=%q, fallback to %d", key, raw, fallback)
+		} else {
+			seconds = parsed
+		}
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// intFromEnv membaca env berisi bilangan bulat tidak negatif.
+func intFromEnv(key string, fallback int) int {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(raw)
+	if err != nil || parsed < 0 {
+		log.Printf("Invalid %s=%q, fallback to %d", key, raw, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+// millisFromEnv membaca env berisi jumlah milidetik.
+func millisFromEnv(key string, fallback int) time.Duration {
+	return time.Duration(intFromEnv(key, fallback)) * time.Millisecond
+}
+
+// floatFromEnv membaca env berisi pecahan antara 0 dan 1.
+func floatFromEnv(key string, fallback float64) float64 {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseFloat(raw, 64)
+	if err != nil || parsed < 0 || parsed > 1 {
+		log.Printf("Invalid %s=%q, fallback to %v", key, raw, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+// statusesFromEnv membaca env berisi daftar status HTTP dipisah koma (contoh: "429,502,503").
+func statusesFromEnv(key string, fallback []int) []int {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	var statuses []int
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		status, err := strconv.Atoi(item)
+		if err != nil || status < 100 || status > 599 {
+			log.Printf("Invalid %s=%q, fallback to %v", key, raw, fallback)
+			return fallback
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
 }
@@ -0,0 +1,185 @@
+package farerule
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DefaultRiderType dipakai kalau request tidak menyebutkan kategori penumpang.
+const DefaultRiderType = "umum"
+
+// RiderType adalah aturan tarif untuk satu kategori penumpang.
+// - DiscountPercent → potongan dalam persen dari tarif dasar (0 - 100).
+// - DiscountAmount  → potongan tetap dalam rupiah, dihitung setelah potongan persen.
+// - DailyCap        → batas total tarif per hari (0 = tanpa batas).
+// - ValidFrom/Until → masa berlaku ("2006-01-02", opsional), biasanya untuk kategori promo.
+type RiderType struct {
+	ID              string `yaml:"id" json:"id"`
+	Name            string `yaml:"name" json:"nama"`
+	DiscountPercent int64  `yaml:"discount_percent" json:"diskon_persen"`
+	DiscountAmount  int64  `yaml:"discount_amount" json:"diskon_rupiah"`
+	DailyCap        int64  `yaml:"daily_cap" json:"batas_harian"`
+	ValidFrom       string `yaml:"valid_from" json:"berlaku_dari,omitempty"`
+	ValidUntil      string `yaml:"valid_until" json:"berlaku_sampai,omitempty"`
+}
+
+// ErrNotValidOnDate dikembalikan Apply kalau tanggal perjalanan di luar masa berlaku kategori.
+var ErrNotValidOnDate = errors.New("rider type is not valid on this date")
+
+// Result adalah hasil penerapan aturan tarif pada satu perjalanan.
+type Result struct {
+	RiderType RiderType
+	Base      int64
+	Discount  int64
+	Final     int64
+}
+
+// file adalah struktur file YAML aturan tarif, contoh:
+//
+//	rider_types:
+//	  - id: pelajar
+//	    name: Pelajar
+//	    discount_percent: 50
+type file struct {
+	RiderTypes []RiderType `yaml:"rider_types"`
+}
+
+// Engine menyimpan aturan tarif per kategori penumpang yang dibaca dari file YAML.
+// Aman dipakai bersamaan oleh banyak goroutine dan bisa di-reload saat runtime.
+type Engine struct {
+	path string
+
+	mu       sync.RWMutex
+	types    map[string]RiderType
+	loadedAt time.Time
+}
+
+// New membuat Engine yang akan membaca file di path saat Reload dipanggil.
+// Sebelum file berhasil dibaca, hanya kategori "umum" (tanpa potongan) yang dikenal.
+func New(path string) *Engine {
+	return &Engine{
+		path: path,
+		types: map[string]RiderType{
+			DefaultRiderType: {ID: DefaultRiderType, Name: "Umum"},
+		},
+	}
+}
+
+// Reload membaca ulang file aturan tarif. Kalau file gagal dibaca atau isinya tidak valid,
+// aturan lama tetap dipakai dan error dikembalikan.
+func (e *Engine) Reload() error {
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return err
+	}
+
+	var parsed file
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+
+	types := map[string]RiderType{
+		DefaultRiderType: {ID: DefaultRiderType, Name: "Umum"},
+	}
+	for _, t := range parsed.RiderTypes {
+		if err := validate(t); err != nil {
+			return err
+		}
+		types[t.ID] = t
+	}
+
+	e.mu.Lock()
+	e.types = types
+	e.loadedAt = time.Now()
+	e.mu.Unlock()
+
+	return nil
+}
+
+// Apply menerapkan aturan kategori riderType pada tarif dasar base untuk perjalanan pada tanggal on.
+func (e *Engine) Apply(riderType string, base int64, on time.Time) (Result, error) {
+	if riderType == "" {
+		riderType = DefaultRiderType
+	}
+
+	e.mu.RLock()
+	rule, ok := e.types[riderType]
+	e.mu.RUnlock()
+	if !ok {
+		return Result{}, fmt.Errorf("unknown rider_type %q", riderType)
+	}
+
+	date := on.Format("2006-01-02")
+	if (rule.ValidFrom != "" && date < rule.ValidFrom) || (rule.ValidUntil != "" && date > rule.ValidUntil) {
+		return Result{}, fmt.Errorf("rider_type %q is not valid on %s: %w", riderType, date, ErrNotValidOnDate)
+	}
+
+	discount := base*rule.DiscountPercent/100 + rule.DiscountAmount
+	if discount > base {
+		discount = base
+	}
+
+	return Result{
+		RiderType: rule,
+		Base:      base,
+		Discount:  discount,
+		Final:     base - discount,
+	}, nil
+}
+
+// List mengembalikan semua kategori penumpang, diurutkan berdasarkan ID.
+func (e *Engine) List() []RiderType {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	list := make([]RiderType, 0, len(e.types))
+	for _, t := range e.types {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+
+	return list
+}
+
+// LoadedAt mengembalikan waktu terakhir file aturan berhasil dibaca.
+func (e *Engine) LoadedAt() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.loadedAt
+}
+
+// Path mengembalikan lokasi file aturan tarif.
+func (e *Engine) Path() string {
+	return e.path
+}
+
+func validate(t RiderType) error {
+	if t.ID == "" {
+		return errors.New("rider type id is required")
+	}
+	if t.DiscountPercent < 0 || t.DiscountPercent > 100 {
+		return fmt.Errorf("rider type %s: discount_percent must be between 0 and 100", t.ID)
+	}
+	if t.DiscountAmount < 0 || t.DailyCap < 0 {
+		return fmt.Errorf("rider type %s: discount_amount and daily_cap must not be negative", t.ID)
+	}
+	for _, date := range []string{t.ValidFrom, t.ValidUntil} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("rider type %s: invalid date %q", t.ID, date)
+		}
+	}
+
+	return nil
+}
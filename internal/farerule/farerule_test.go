@@ -0,0 +1,140 @@
+package farerule
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testRules = `
+rider_types:
+  - id: pelajar
+    name: Pelajar
+    discount_percent: 50
+    daily_cap: 14000
+  - id: potongan
+    name: Potongan Tetap
+    discount_amount: 1000
+  - id: gabungan
+    name: Persen dan Tetap
+    discount_percent: 10
+    discount_amount: 2000
+  - id: gratis
+    name: Gratis
+    discount_percent: 100
+    discount_amount: 5000
+  - id: promo
+    name: Promo
+    discount_amount: 1000
+    valid_from: 2026-12-01
+    valid_until: 2026-12-31
+`
+
+// loadEngine menulis content ke file sementara lalu membaca Engine dari file itu.
+func loadEngine(t *testing.T, content string) (*Engine, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fare_rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := New(path)
+	return engine, engine.Reload()
+}
+
+func day(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestApply(t *testing.T) {
+	engine, err := loadEngine(t, testRules)
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		riderType    string
+		base         int64
+		on           string
+		wantDiscount int64
+		wantFinal    int64
+		wantCap      int64
+		wantNotValid bool
+		wantErr      bool
+	}{
+		{name: "empty rider type is umum", base: 14000, on: "2026-11-02", wantFinal: 14000},
+		{name: "umum has no discount", riderType: "umum", base: 14000, on: "2026-11-02", wantFinal: 14000},
+		{name: "percent discount", riderType: "pelajar", base: 14000, on: "2026-11-02", wantDiscount: 7000, wantFinal: 7000, wantCap: 14000},
+		{name: "percent discount rounds down", riderType: "pelajar", base: 3001, on: "2026-11-02", wantDiscount: 1500, wantFinal: 1501, wantCap: 14000},
+		{name: "fixed discount", riderType: "potongan", base: 3000, on: "2026-11-02", wantDiscount: 1000, wantFinal: 2000},
+		{name: "fixed discount after percent", riderType: "gabungan", base: 10000, on: "2026-11-02", wantDiscount: 3000, wantFinal: 7000},
+		{name: "discount never exceeds base", riderType: "gratis", base: 3000, on: "2026-11-02", wantDiscount: 3000, wantFinal: 0},
+		{name: "fixed discount larger than base", riderType: "potongan", base: 500, on: "2026-11-02", wantDiscount: 500, wantFinal: 0},
+		{name: "first valid day", riderType: "promo", base: 3000, on: "2026-12-01", wantDiscount: 1000, wantFinal: 2000},
+		{name: "last valid day", riderType: "promo", base: 3000, on: "2026-12-31", wantDiscount: 1000, wantFinal: 2000},
+		{name: "before valid_from", riderType: "promo", base: 3000, on: "2026-11-30", wantNotValid: true},
+		{name: "after valid_until", riderType: "promo", base: 3000, on: "2027-01-01", wantNotValid: true},
+		{name: "unknown rider type", riderType: "vip", base: 3000, on: "2026-11-02", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := engine.Apply(tt.riderType, tt.base, day(tt.on))
+			if tt.wantNotValid || tt.wantErr {
+				if err == nil {
+					t.Fatalf("Apply() = %+v, want error", result)
+				}
+				if got := errors.Is(err, ErrNotValidOnDate); got != tt.wantNotValid {
+					t.Errorf("errors.Is(err, ErrNotValidOnDate) = %v, want %v (err %v)", got, tt.wantNotValid, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			if result.Base != tt.base || result.Discount != tt.wantDiscount || result.Final != tt.wantFinal || result.RiderType.DailyCap != tt.wantCap {
+				t.Errorf("Apply() = base %d, discount %d, final %d, cap %d; want %d, %d, %d, %d",
+					result.Base, result.Discount, result.Final, result.RiderType.DailyCap, tt.base, tt.wantDiscount, tt.wantFinal, tt.wantCap)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid rules", content: testRules},
+		{name: "broken yaml", content: "rider_types: [", wantErr: true},
+		{name: "missing id", content: "rider_types:\n  - name: Tanpa ID\n", wantErr: true},
+		{name: "percent above 100", content: "rider_types:\n  - id: x\n    discount_percent: 150\n", wantErr: true},
+		{name: "negative daily cap", content: "rider_types:\n  - id: x\n    daily_cap: -1\n", wantErr: true},
+		{name: "invalid date", content: "rider_types:\n  - id: x\n    valid_from: 01-12-2026\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := loadEngine(t, tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			// Aturan lama (hanya umum) tetap dipakai kalau file tidak valid
+			if list := engine.List(); len(list) != 1 || list[0].ID != DefaultRiderType || !engine.LoadedAt().IsZero() {
+				t.Errorf("List() = %+v after failed reload, want only %s", list, DefaultRiderType)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package timetable
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxHour adalah jam tertinggi yang diterima. Jam 24 ke atas (misalnya "24:15") dipakai operator
+// untuk kereta malam yang berangkat lewat tengah malam tapi masih milik hari operasional yang sama.
+const MaxHour = 29
+
+var errFormat = errors.New("invalid format, use HH:MM or HH:MM:SS")
+
+// Issue adalah satu entri jadwal yang tidak bisa di-parse dan dilewati.
+type Issue struct {
+	Index  int    `json:"index"` // urutan di antara entri yang tidak kosong (setelah dipisah dan separator berlebih dibuang), mulai dari 0
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// Parse mengubah string jadwal mentah dari API MRT ("05:00:00,05:10:00,...") menjadi
+// daftar jam keberangkatan, dihitung dari tengah malam tanggal operasional.
+//   - Entri dipisah koma, titik koma, spasi atau baris baru; spasi berlebih diabaikan.
+//   - Format yang diterima: "HH:MM", "HH:MM:SS", termasuk "24:xx" untuk lewat tengah malam.
+//   - Entri yang rusak dilewati dan dilaporkan di issues, entri lain tetap dipakai.
+//   - Hasilnya terurut dan tanpa duplikat.
+func Parse(raw string) (offsets []time.Duration, issues []Issue) {
+	entries := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+
+	for i, entry := range entries {
+		offset, err := ParseClock(entry)
+		if err != nil {
+			issues = append(issues, Issue{Index: i, Value: entry, Reason: err.Error()})
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+
+	return dedupe(offsets), issues
+}
+
+// ParseClock mengubah satu entri "HH:MM" atau "HH:MM:SS" menjadi durasi dari tengah malam.
+func ParseClock(entry string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(entry), ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, errFormat
+	}
+
+	var values [3]int
+	for i, part := range parts {
+		if len(part) == 0 || len(part) > 2 || strings.Trim(part, "0123456789") != "" {
+			return 0, errFormat
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, errFormat
+		}
+		values[i] = n
+	}
+
+	hour, minute, second := values[0], values[1], values[2]
+	switch {
+	case hour > MaxHour:
+		return 0, fmt.Errorf("hour %d out of range (0-%d)", hour, MaxHour)
+	case minute > 59:
+		return 0, fmt.Errorf("minute %d out of range (0-59)", minute)
+	case second > 59:
+		return 0, fmt.Errorf("second %d out of range (0-59)", second)
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, nil
+}
+
+// dedupe membuang nilai yang sama berurutan dari slice yang sudah terurut.
+func dedupe(offsets []time.Duration) []time.Duration {
+	if len(offsets) < 2 {
+		return offsets
+	}
+
+	resp := offsets[:1]
+	for _, offset := range offsets[1:] {
+		if offset != resp[len(resp)-1] {
+			resp = append(resp, offset)
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,132 @@
+package timetable
+
+import (
+	"testing"
+	"time"
+)
+
+func clock(h, m, s int) time.Duration {
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{entry: "05:00", want: clock(5, 0, 0)},
+		{entry: "05:00:30", want: clock(5, 0, 30)},
+		{entry: "5:07", want: clock(5, 7, 0)},
+		{entry: " 06:10 ", want: clock(6, 10, 0)},
+		{entry: "00:00", want: 0},
+		{entry: "24:00", want: clock(24, 0, 0)},
+		{entry: "24:15:00", want: clock(24, 15, 0)},
+		{entry: "29:59:59", want: clock(29, 59, 59)},
+		{entry: "30:00", wantErr: true},
+		{entry: "12:60", wantErr: true},
+		{entry: "12:00:60", wantErr: true},
+		{entry: "12", wantErr: true},
+		{entry: "12:00:00:00", wantErr: true},
+		{entry: "12:5a", wantErr: true},
+		{entry: "-1:00", wantErr: true},
+		{entry: "+1:00", wantErr: true},
+		{entry: "123:00", wantErr: true},
+		{entry: "12::00", wantErr: true},
+		{entry: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := ParseClock(tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseClock(%q) = %v, want error", tt.entry, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseClock(%q) error = %v", tt.entry, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClock(%q) = %v, want %v", tt.entry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		want       []time.Duration
+		wantIssues []Issue
+	}{
+		{
+			name: "comma separated",
+			raw:  "05:00:00,05:10:00,05:20:00",
+			want: []time.Duration{clock(5, 0, 0), clock(5, 10, 0), clock(5, 20, 0)},
+		},
+		{
+			name: "mixed separators and spacing",
+			raw:  " 05:00; 05:10 ,\n05:20\t05:30\r\n",
+			want: []time.Duration{clock(5, 0, 0), clock(5, 10, 0), clock(5, 20, 0), clock(5, 30, 0)},
+		},
+		{
+			name: "trailing and doubled separators",
+			raw:  "05:00,,05:10,",
+			want: []time.Duration{clock(5, 0, 0), clock(5, 10, 0)},
+		},
+		{
+			name: "sorted and deduplicated",
+			raw:  "05:20,05:00,05:10,05:00,05:00:00",
+			want: []time.Duration{clock(5, 0, 0), clock(5, 10, 0), clock(5, 20, 0)},
+		},
+		{
+			name: "after midnight entries sort last",
+			raw:  "24:10,23:50,24:00",
+			want: []time.Duration{clock(23, 50, 0), clock(24, 0, 0), clock(24, 10, 0)},
+		},
+		{
+			name: "broken entries skipped and reported",
+			raw:  "05:00,5.10,05:20,31:00",
+			want: []time.Duration{clock(5, 0, 0), clock(5, 20, 0)},
+			wantIssues: []Issue{
+				{Index: 1, Value: "5.10"},
+				{Index: 3, Value: "31:00"},
+			},
+		},
+		{
+			name: "empty",
+			raw:  "",
+		},
+		{
+			name:       "nothing valid",
+			raw:        "pagi,siang",
+			wantIssues: []Issue{{Index: 0, Value: "pagi"}, {Index: 1, Value: "siang"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, issues := Parse(tt.raw)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Parse(%q) = %v, want %v", tt.raw, got, tt.want)
+				}
+			}
+
+			if len(issues) != len(tt.wantIssues) {
+				t.Fatalf("Parse(%q) issues = %+v, want %+v", tt.raw, issues, tt.wantIssues)
+			}
+			for i, issue := range issues {
+				want := tt.wantIssues[i]
+				if issue.Index != want.Index || issue.Value != want.Value || issue.Reason == "" {
+					t.Errorf("issue %d = %+v, want index %d value %q with a reason", i, issue, want.Index, want.Value)
+				}
+			}
+		})
+	}
+}
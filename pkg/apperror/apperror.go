@@ -0,0 +1,105 @@
+package apperror
+
+import "errors"
+
+// Kind adalah golongan error domain. Setiap Kind dipetakan ke satu status HTTP
+// di satu tempat (lihat response.Fail), jadi handler tidak perlu menebak status sendiri.
+type Kind string
+
+const (
+	KindInvalidInput        Kind = "invalid_input"        // input dari client tidak valid → 400
+	KindUnauthorized        Kind = "unauthorized"         // token tidak ada / salah (endpoint admin) → 401
+	KindNotFound            Kind = "not_found"            // stasiun/lintasan/data yang diminta tidak ada → 404
+	KindNoService           Kind = "no_service"           // data ada, tapi tidak ada layanan kereta pada waktu itu → 404
+	KindUpstreamMalformed   Kind = "upstream_malformed"   // API MRT membalas, tapi datanya rusak/tidak bisa dipakai → 502
+	KindUpstreamUnavailable Kind = "upstream_unavailable" // API MRT tidak bisa dihubungi / sedang down → 503
+	KindUpstreamTimeout     Kind = "upstream_timeout"     // API MRT terlalu lama membalas → 504
+	KindInternal            Kind = "internal"             // error lain yang tidak terduga → 500
+)
+
+// FieldError menjelaskan masalah pada satu field input, contoh:
+// {Field: "from", Code: "MISSING_STATION", Message: "from is required"}.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error adalah error domain yang membawa Kind dan Code.
+// - Code    → kode stabil yang bisa dibaca mesin (lihat codes.go).
+// - Message → pesan untuk client; kalau kosong dipakai pesan dari Err.
+// - Fields  → detail per field untuk error validasi (opsional).
+// - Err     → error asli (opsional), tetap bisa dicek dengan errors.Is / errors.As.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return string(e.Kind)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New membuat error domain baru dengan pesan message.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap membungkus err menjadi error domain, pesannya mengikuti err.
+func Wrap(kind Kind, code string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Err: err}
+}
+
+// InvalidInput adalah singkatan New(KindInvalidInput, code, message).
+func InvalidInput(code, message string) *Error {
+	return New(KindInvalidInput, code, message)
+}
+
+// NotFound adalah singkatan New(KindNotFound, code, message).
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// NoService adalah singkatan New(KindNoService, code, message).
+func NoService(code, message string) *Error {
+	return New(KindNoService, code, message)
+}
+
+// Validation membuat error KindInvalidInput berkode CodeValidationFailed dengan detail per field.
+// Kalau hanya ada satu field, pesan error mengikuti pesan field tersebut.
+func Validation(fields ...FieldError) *Error {
+	message := "request validation failed"
+	if len(fields) == 1 {
+		message = fields[0].Message
+	}
+
+	return &Error{
+		Kind:    KindInvalidInput,
+		Code:    CodeValidationFailed,
+		Message: message,
+		Fields:  fields,
+	}
+}
+
+// As mencari *Error di dalam rantai err.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+
+	return nil, false
+}
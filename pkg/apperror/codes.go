@@ -0,0 +1,59 @@
+package apperror
+
+// Kode error yang stabil. Client boleh bergantung pada nilai-nilai ini
+// (misalnya untuk menampilkan pesan terjemahan), jadi jangan ubah nilai yang sudah ada;
+// tambahkan kode baru dan daftarkan juga di katalog response.Catalogue.
+const (
+	// Input tidak valid (400)
+	CodeInvalidInput      = "INVALID_INPUT"
+	CodeValidationFailed  = "VALIDATION_FAILED"
+	CodeInvalidBody       = "INVALID_BODY"
+	CodeInvalidFormat     = "INVALID_FORMAT"
+	CodeInvalidAt         = "INVALID_AT"
+	CodeInvalidDayType    = "INVALID_DAY_TYPE"
+	CodeInvalidLimit      = "INVALID_LIMIT"
+	CodeInvalidDest       = "INVALID_DESTINATION"
+	CodeInvalidMode       = "INVALID_MODE"
+	CodeInvalidTime       = "INVALID_TIME"
+	CodeInvalidTimeWindow = "INVALID_TIME_WINDOW"
+	CodeInvalidRiderType  = "INVALID_RIDER_TYPE"
+	CodeInvalidDate       = "INVALID_DATE"
+	CodeInvalidMonth      = "INVALID_MONTH"
+	CodeMissingStation    = "MISSING_STATION"
+	CodeInvalidStationID  = "INVALID_STATION_ID"
+	CodeSameStation       = "SAME_STATION"
+	CodeMissingLegs       = "MISSING_LEGS"
+	CodeTooManyLegs       = "TOO_MANY_LEGS"
+	CodeReloadFailed      = "RELOAD_FAILED"
+
+	// Tidak terautentikasi (401)
+	CodeUnauthorized = "UNAUTHORIZED"
+
+	// Data tidak ditemukan / tidak ada layanan (404)
+	CodeNotFound          = "NOT_FOUND"
+	CodeRouteNotFound     = "ROUTE_NOT_FOUND"
+	CodeStationNotFound   = "STATION_NOT_FOUND"
+	CodeLineNotFound      = "LINE_NOT_FOUND"
+	CodeFareNotFound      = "FARE_NOT_FOUND"
+	CodeErrorCodeNotFound = "ERROR_CODE_NOT_FOUND"
+	CodeNoNextTrain       = "NO_NEXT_TRAIN"
+
+	// Data upstream rusak (502)
+	CodeDataQuality         = "DATA_QUALITY"
+	CodeLineUnavailable     = "LINE_UNAVAILABLE"
+	CodeUpstreamMalformed   = "UPSTREAM_MALFORMED"
+	CodeUpstreamSchema      = "UPSTREAM_SCHEMA_INVALID"
+	CodeUpstreamBadResponse = "UPSTREAM_BAD_RESPONSE"
+
+	// Upstream tidak tersedia (503)
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamCircuitOpen = "UPSTREAM_CIRCUIT_OPEN"
+	CodeSnapshotUnavailable = "SNAPSHOT_UNAVAILABLE"
+
+	// Timeout (504)
+	CodeUpstreamTimeout = "UPSTREAM_TIMEOUT"
+	CodeRequestTimeout  = "REQUEST_TIMEOUT"
+
+	// Lainnya (500)
+	CodeInternal = "INTERNAL_ERROR"
+)
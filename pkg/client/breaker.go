@@ -0,0 +1,149 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen dikembalikan tanpa memanggil upstream selama circuit breaker terbuka.
+var ErrCircuitOpen = errors.New("upstream circuit breaker is open")
+
+// Status circuit breaker.
+const (
+	BreakerClosed   = "closed"    // normal, semua request diteruskan ke upstream
+	BreakerOpen     = "open"      // upstream dianggap down, request langsung gagal
+	BreakerHalfOpen = "half-open" // satu request percobaan untuk mengecek upstream sudah pulih
+)
+
+// BreakerPolicy mengatur kapan circuit breaker terbuka.
+// - FailureThreshold → jumlah kegagalan berturut-turut sebelum terbuka (0 = breaker nonaktif).
+// - OpenTimeout      → lama breaker terbuka sebelum mencoba satu request percobaan (half-open).
+type BreakerPolicy struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// DefaultBreakerPolicy: terbuka setelah 5 kegagalan berturut-turut, dicoba lagi setelah 30 detik.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// BreakerState adalah kondisi circuit breaker saat ini, untuk endpoint admin.
+type BreakerState struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailureThreshold    int        `json:"failure_threshold"`
+	OpenTimeoutSeconds  float64    `json:"open_timeout_seconds"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // Waktu paling cepat request percobaan dikirim
+	Opens               uint64     `json:"opens"`              // Berapa kali breaker terbuka sejak server start
+	Rejected            uint64     `json:"rejected"`           // Request yang ditolak tanpa memanggil upstream
+}
+
+// breaker adalah circuit breaker sederhana berbasis kegagalan berturut-turut.
+type breaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	opens    uint64
+	rejected uint64
+}
+
+func newBreaker(policy BreakerPolicy) *breaker {
+	return &breaker{policy: policy, state: BreakerClosed}
+}
+
+// allow mengecek apakah request boleh diteruskan ke upstream.
+// Setelah OpenTimeout lewat, hanya satu request percobaan yang diizinkan sampai hasilnya dicatat.
+func (b *breaker) allow(now time.Time) bool {
+	if b.policy.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.policy.OpenTimeout {
+			b.rejected++
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record mencatat hasil request yang diizinkan oleh allow.
+func (b *breaker) record(success bool, now time.Time) {
+	if b.policy.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+		if b.state != BreakerOpen {
+			b.opens++
+		}
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+// release dipanggil kalau request yang diizinkan tidak menghasilkan keputusan
+// (misalnya dibatalkan client), supaya request percobaan berikutnya tetap bisa jalan.
+func (b *breaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) snapshot() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BreakerState{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.policy.FailureThreshold,
+		OpenTimeoutSeconds:  b.policy.OpenTimeout.Seconds(),
+		Opens:               b.opens,
+		Rejected:            b.rejected,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := openedAt.Add(b.policy.OpenTimeout)
+		state.OpenedAt = &openedAt
+		state.RetryAt = &retryAt
+	}
+
+	return state
+}
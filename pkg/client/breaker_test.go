@@ -0,0 +1,137 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerTransitions(t *testing.T) {
+	start := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	policy := BreakerPolicy{FailureThreshold: 2, OpenTimeout: 30 * time.Second}
+
+	// step adalah satu langkah: allow pada waktu at, lalu (kalau diizinkan) catat hasilnya.
+	// result: "ok", "fail", atau "release" (request dibatalkan tanpa keputusan).
+	type step struct {
+		at        time.Duration
+		result    string
+		wantAllow bool
+		wantState string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "stays closed below threshold",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: time.Second, result: "ok", wantAllow: true, wantState: BreakerClosed},
+				{at: 2 * time.Second, result: "fail", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "opens at threshold and rejects until timeout",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: time.Second, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 10 * time.Second, wantAllow: false, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "half-open probe success closes",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 31 * time.Second, result: "ok", wantAllow: true, wantState: BreakerClosed},
+				{at: 32 * time.Second, result: "fail", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "half-open probe failure reopens",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 31 * time.Second, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 40 * time.Second, wantAllow: false, wantState: BreakerOpen},
+				{at: 62 * time.Second, result: "ok", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "only one probe while half-open",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 31 * time.Second, wantAllow: true, wantState: BreakerHalfOpen}, // probe belum selesai
+				{at: 31 * time.Second, wantAllow: false, wantState: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "released probe lets the next request probe",
+			steps: []step{
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerClosed},
+				{at: 0, result: "fail", wantAllow: true, wantState: BreakerOpen},
+				{at: 31 * time.Second, result: "release", wantAllow: true, wantState: BreakerHalfOpen},
+				{at: 32 * time.Second, result: "ok", wantAllow: true, wantState: BreakerClosed},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(policy)
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				allowed := b.allow(now)
+				if allowed != s.wantAllow {
+					t.Fatalf("step %d: allow() = %v, want %v", i, allowed, s.wantAllow)
+				}
+				if allowed {
+					switch s.result {
+					case "ok":
+						b.record(true, now)
+					case "fail":
+						b.record(false, now)
+					case "release":
+						b.release()
+					}
+				}
+				if got := b.snapshot().State; got != s.wantState {
+					t.Fatalf("step %d: state = %q, want %q", i, got, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := newBreaker(BreakerPolicy{})
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		if !b.allow(now) {
+			t.Fatalf("allow() = false on attempt %d, breaker with threshold 0 must stay closed", i)
+		}
+		b.record(false, now)
+	}
+	if state := b.snapshot(); state.State != BreakerClosed || state.Opens != 0 {
+		t.Errorf("snapshot() = %+v, want closed and never opened", state)
+	}
+}
+
+func TestBreakerCounters(t *testing.T) {
+	start := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	b := newBreaker(BreakerPolicy{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	b.allow(start)
+	b.record(false, start)
+	// Masih dalam OpenTimeout, dua-duanya ditolak
+	b.allow(start.Add(time.Second))
+	b.allow(start.Add(2 * time.Second))
+
+	state := b.snapshot()
+	if state.Opens != 1 || state.Rejected != 2 {
+		t.Errorf("Opens = %d, Rejected = %d, want 1 and 2", state.Opens, state.Rejected)
+	}
+	if state.RetryAt == nil || !state.RetryAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("RetryAt = %v, want %v", state.RetryAt, start.Add(time.Minute))
+	}
+}
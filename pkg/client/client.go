@@ -1,20 +1,39 @@
 package client
 
 import (
-	"errors"
+	"context"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 )
 
+// StatusError dikembalikan kalau upstream membalas dengan status selain 200.
+// RetryAfter diisi dari header Retry-After (kalau ada), dipakai oleh Client saat retry.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return "unexpected status code: " + e.Status
+}
+
 // DoRequest adalah fungsi helper untuk melakukan HTTP GET request.
+// - Param ctx: request dibatalkan kalau ctx dibatalkan atau deadline-nya lewat.
 // - Param client: http.Client yang dipakai (sudah ada timeout dll).
 // - Param url: alamat tujuan request.
 // - Return []byte: isi response dalam bentuk byte.
 // - Return error: error kalau ada masalah.
-func DoRequest(client *http.Client, url string) ([]byte, error) {
+func DoRequest(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	// Kirim request GET ke URL
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +43,11 @@ func DoRequest(client *http.Client, url string) ([]byte, error) {
 
 	// Kalau status code bukan 200 (OK), anggap error
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("unexpected status code: " + resp.Status)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	// Baca semua isi response body jadi []byte
@@ -36,3 +59,24 @@ func DoRequest(client *http.Client, url string) ([]byte, error) {
 	// Kembalikan isi response
 	return body, nil
 }
+
+// parseRetryAfter membaca header Retry-After, bisa berupa jumlah detik ("120")
+// atau tanggal HTTP ("Wed, 21 Oct 2026 07:28:00 GMT"). Nilai tidak valid dianggap 0.
+func parseRetryAfter(raw string, now time.Time) time.Duration {
+	if raw == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(raw); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
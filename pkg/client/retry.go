@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy mengatur kapan dan seberapa sering request ke upstream diulang.
+// - MaxAttempts     → jumlah percobaan total, termasuk percobaan pertama (1 = tanpa retry).
+// - BaseDelay       → jeda sebelum retry pertama, dikali 2 setiap retry berikutnya.
+// - MaxDelay        → batas atas jeda; Retry-After yang lebih lama dari ini tidak ditunggu.
+// - Jitter          → porsi acak dari jeda (0 - 1), supaya banyak instance tidak retry bersamaan.
+// - RetryableStatus → status HTTP yang dianggap sementara dan layak diulang.
+type RetryPolicy struct {
+	MaxAttempts     int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	Jitter          float64
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy: 3 percobaan, jeda 200ms → 400ms (maks 2 detik), jitter 20%.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Jitter:      0.2,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// backoff menghitung jeda sebelum retry ke-n (mulai dari 1).
+// BaseDelay 0 berarti retry langsung tanpa jeda. Kalau BaseDelay * 2^(n-1) tidak muat di time.Duration,
+// jedanya dianggap tak terhingga lalu dibatasi MaxDelay seperti biasa.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	if p.BaseDelay <= 0 || retry < 1 {
+		return 0
+	}
+
+	delay := time.Duration(math.MaxInt64)
+	if shift := retry - 1; shift < 63 && p.BaseDelay <= delay>>shift {
+		delay = p.BaseDelay << shift
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		// Dikurangi, bukan dikali ulang, supaya jeda yang sudah mentok di MaxInt64 tidak overflow lagi
+		delay -= time.Duration(float64(delay) * jitter * (1 - rand.Float64()))
+	}
+
+	return delay
+}
+
+// Stats adalah statistik request Client ke upstream.
+// - Requests  → jumlah pemanggilan Get.
+// - Attempts  → jumlah HTTP request yang benar-benar dikirim (termasuk retry).
+// - Retries   → jumlah percobaan ulang.
+// - Failures  → Get yang tetap gagal setelah semua percobaan.
+// - LastError → pesan error terakhir dari upstream (kosong kalau belum pernah gagal).
+type Stats struct {
+	Requests  uint64 `json:"requests"`
+	Attempts  uint64 `json:"attempts"`
+	Retries   uint64 `json:"retries"`
+	Failures  uint64 `json:"failures"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Client membungkus http.Client dengan retry policy, circuit breaker, dan statistik.
+// Aman dipakai bersamaan oleh banyak goroutine.
+type Client struct {
+	http    *http.Client
+	policy  RetryPolicy
+	breaker *breaker
+
+	requests atomic.Uint64
+	attempts atomic.Uint64
+	retries  atomic.Uint64
+	failures atomic.Uint64
+
+	mu      sync.Mutex
+	lastErr string
+}
+
+// Option dipakai untuk mengatur perilaku tambahan Client saat dibuat.
+type Option func(*Client)
+
+// WithBreaker mengaktifkan circuit breaker dengan policy tertentu.
+// Tanpa option ini, Client memakai DefaultBreakerPolicy.
+func WithBreaker(policy BreakerPolicy) Option {
+	return func(c *Client) {
+		c.breaker = newBreaker(policy)
+	}
+}
+
+// NewClient membuat Client baru. MaxAttempts < 1 dianggap 1 (tanpa retry).
+func NewClient(httpClient *http.Client, policy RetryPolicy, opts ...Option) *Client {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	c := &Client{
+		http:    httpClient,
+		policy:  policy,
+		breaker: newBreaker(DefaultBreakerPolicy()),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Get melakukan HTTP GET ke url, diulang sesuai retry policy kalau error-nya sementara.
+// Jeda antar percobaan ikut berhenti kalau ctx dibatalkan.
+// Selama circuit breaker terbuka, Get langsung mengembalikan ErrCircuitOpen tanpa memanggil upstream.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	c.requests.Add(1)
+
+	if !c.breaker.allow(time.Now()) {
+		c.failures.Add(1)
+		return nil, ErrCircuitOpen
+	}
+
+	for attempt := 1; ; attempt++ {
+		c.attempts.Add(1)
+		body, err := DoRequest(ctx, c.http, url)
+		if err == nil {
+			c.breaker.record(true, time.Now())
+			return body, nil
+		}
+
+		delay, retry := c.retryDelay(ctx, err, attempt)
+		if !retry {
+			c.finish(ctx, err)
+			return nil, err
+		}
+
+		c.retries.Add(1)
+		log.Printf("client: GET %s attempt %d/%d failed (%v), retrying in %s", url, attempt, c.policy.MaxAttempts, err, delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			c.finish(ctx, err)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// finish mencatat Get yang gagal ke statistik dan circuit breaker.
+// Hanya kegagalan sisi upstream (error jaringan atau status yang layak di-retry) yang membuka breaker;
+// request yang dibatalkan client dan status seperti 404 tidak dihitung.
+func (c *Client) finish(ctx context.Context, err error) {
+	c.failures.Add(1)
+	c.setLastError(err)
+
+	switch {
+	case ctx.Err() != nil:
+		c.breaker.release()
+	case c.isUpstreamFailure(err):
+		c.breaker.record(false, time.Now())
+	default:
+		c.breaker.record(true, time.Now())
+	}
+}
+
+// isUpstreamFailure mengecek apakah err menandakan upstream sedang bermasalah.
+func (c *Client) isUpstreamFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(c.policy.RetryableStatus, statusErr.StatusCode)
+	}
+
+	return true
+}
+
+// retryDelay menentukan apakah err layak diulang pada percobaan ke-attempt, dan berapa lama jedanya.
+func (c *Client) retryDelay(ctx context.Context, err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.policy.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	delay := c.policy.backoff(attempt)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if !slices.Contains(c.policy.RetryableStatus, statusErr.StatusCode) {
+			return 0, false
+		}
+		if statusErr.RetryAfter > 0 {
+			// Upstream minta menunggu lebih lama dari batas kita → jangan retry
+			if c.policy.MaxDelay > 0 && statusErr.RetryAfter > c.policy.MaxDelay {
+				return 0, false
+			}
+			delay = statusErr.RetryAfter
+		}
+	}
+
+	// Error jaringan (timeout, koneksi ditolak, dll) selalu dianggap sementara
+	return delay, true
+}
+
+func (c *Client) setLastError(err error) {
+	c.mu.Lock()
+	c.lastErr = err.Error()
+	c.mu.Unlock()
+}
+
+// Stats mengembalikan statistik request ke upstream sejak server start.
+func (c *Client) Stats() Stats {
+	c.mu.Lock()
+	lastErr := c.lastErr
+	c.mu.Unlock()
+
+	return Stats{
+		Requests:  c.requests.Load(),
+		Attempts:  c.attempts.Load(),
+		Retries:   c.retries.Load(),
+		Failures:  c.failures.Load(),
+		LastError: lastErr,
+	}
+}
+
+// Breaker mengembalikan kondisi circuit breaker saat ini.
+func (c *Client) Breaker() BreakerState {
+	return c.breaker.snapshot()
+}
+
+// Policy mengembalikan retry policy yang dipakai Client.
+func (c *Client) Policy() RetryPolicy {
+	return c.policy
+}
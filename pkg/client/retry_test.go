@@ -0,0 +1,56 @@
+package client
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{name: "first retry uses base delay", policy: RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, retry: 1, want: 200 * time.Millisecond},
+		{name: "doubles each retry", policy: RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, retry: 3, want: 800 * time.Millisecond},
+		{name: "capped at max delay", policy: RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}, retry: 5, want: 2 * time.Second},
+		{name: "zero base means no delay", policy: RetryPolicy{MaxDelay: 2 * time.Second}, retry: 3, want: 0},
+		{name: "negative base means no delay", policy: RetryPolicy{BaseDelay: -time.Second, MaxDelay: 2 * time.Second}, retry: 1, want: 0},
+		{name: "retry zero means no delay", policy: RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}, retry: 0, want: 0},
+		{name: "shift overflow capped at max delay", policy: RetryPolicy{BaseDelay: time.Second, MaxDelay: 2 * time.Second}, retry: 70, want: 2 * time.Second},
+		{name: "multiplication overflow capped at max delay", policy: RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Minute}, retry: 40, want: time.Minute},
+		{name: "overflow without max delay saturates", policy: RetryPolicy{BaseDelay: time.Second}, retry: 64, want: math.MaxInt64},
+		{name: "no max delay keeps doubling", policy: RetryPolicy{BaseDelay: time.Second}, retry: 4, want: 8 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.retry); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		retry    int
+		min, max time.Duration
+	}{
+		{name: "20 percent jitter", policy: RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 0.2}, retry: 2, min: 1600 * time.Millisecond, max: 2 * time.Second},
+		{name: "jitter above 1 treated as 1", policy: RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 5}, retry: 1, min: 0, max: time.Second},
+		{name: "saturated delay does not overflow", policy: RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}, retry: 80, min: math.MaxInt64 / 2, max: math.MaxInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tt.policy.backoff(tt.retry); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.retry, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deadline memasang batas waktu pada context setiap request.
+// Context ini diteruskan handler → usecase → service → HTTP client, jadi fetch ke upstream
+// ikut berhenti menunggu saat batas waktunya lewat atau client memutus koneksi.
+// timeout 0 artinya tanpa batas waktu tambahan (hanya pembatalan dari client).
+func Deadline(timeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
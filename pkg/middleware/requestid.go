@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader adalah header tempat request ID dibaca dan dikirim balik.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey adalah key gin.Context tempat request ID disimpan.
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength membatasi request ID dari client supaya tidak dipakai menyelipkan data besar ke log.
+const maxRequestIDLength = 128
+
+// RequestID memberi setiap request sebuah ID. Kalau client (atau load balancer) sudah mengirim
+// X-Request-ID, ID itu dipakai ulang; kalau belum, dibuatkan ID acak.
+// ID dikirim balik lewat header X-Request-ID dan ikut di body response error.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+
+		ctx.Set(RequestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,103 @@
+package response
+
+import (
+	"sort"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+)
+
+// DocsBasePath adalah prefix link dokumentasi kode error (lihat GET /v1/api/errors/:code).
+const DocsBasePath = "/v1/api/errors/"
+
+// CodeInfo adalah satu entri katalog kode error.
+// Status tidak ditulis manual, tapi diturunkan dari Kind lewat kindStatus (sama dengan yang dipakai Fail),
+// jadi status di katalog selalu sama dengan status response sebenarnya.
+type CodeInfo struct {
+	Code        string        `json:"code"`
+	Kind        apperror.Kind `json:"-"`
+	Status      int           `json:"status"`
+	Description string        `json:"description"`
+	DocURL      string        `json:"doc_url"`
+}
+
+// Catalogue adalah daftar semua kode error yang bisa dikembalikan API.
+// Deskripsi ditulis untuk developer; client sebaiknya memetakan Code ke pesan lokal sendiri.
+var Catalogue = buildCatalogue([]CodeInfo{
+	{Code: apperror.CodeInvalidInput, Kind: apperror.KindInvalidInput, Description: "Input request tidak valid."},
+	{Code: apperror.CodeValidationFailed, Kind: apperror.KindInvalidInput, Description: "Satu atau lebih field tidak valid, lihat details."},
+	{Code: apperror.CodeInvalidBody, Kind: apperror.KindInvalidInput, Description: "Body request bukan JSON yang valid."},
+	{Code: apperror.CodeInvalidFormat, Kind: apperror.KindInvalidInput, Description: "Parameter format tidak dikenal, gunakan json atau csv."},
+	{Code: apperror.CodeInvalidAt, Kind: apperror.KindInvalidInput, Description: "Parameter at bukan RFC3339 atau HH:MM."},
+	{Code: apperror.CodeInvalidDayType, Kind: apperror.KindInvalidInput, Description: "Parameter day_type harus biasa atau libur."},
+	{Code: apperror.CodeInvalidLimit, Kind: apperror.KindInvalidInput, Description: "Parameter limit bukan angka atau di luar batas."},
+	{Code: apperror.CodeInvalidDest, Kind: apperror.KindInvalidInput, Description: "Parameter destination harus LB atau HI."},
+	{Code: apperror.CodeInvalidMode, Kind: apperror.KindInvalidInput, Description: "Parameter mode harus remaining atau full."},
+	{Code: apperror.CodeInvalidTime, Kind: apperror.KindInvalidInput, Description: "Jam harus berformat HH:MM."},
+	{Code: apperror.CodeInvalidTimeWindow, Kind: apperror.KindInvalidInput, Description: "Jendela waktu tidak valid, until harus setelah from."},
+	{Code: apperror.CodeInvalidRiderType, Kind: apperror.KindInvalidInput, Description: "Kategori penumpang tidak dikenal."},
+	{Code: apperror.CodeInvalidDate, Kind: apperror.KindInvalidInput, Description: "Tanggal harus berformat YYYY-MM-DD."},
+	{Code: apperror.CodeInvalidMonth, Kind: apperror.KindInvalidInput, Description: "Bulan harus berformat YYYY-MM."},
+	{Code: apperror.CodeMissingStation, Kind: apperror.KindInvalidInput, Description: "Stasiun asal/tujuan wajib diisi."},
+	{Code: apperror.CodeInvalidStationID, Kind: apperror.KindInvalidInput, Description: "ID stasiun harus berupa angka (contoh: 38)."},
+	{Code: apperror.CodeSameStation, Kind: apperror.KindInvalidInput, Description: "Stasiun asal dan tujuan tidak boleh sama."},
+	{Code: apperror.CodeMissingLegs, Kind: apperror.KindInvalidInput, Description: "Daftar perjalanan (legs) wajib diisi."},
+	{Code: apperror.CodeTooManyLegs, Kind: apperror.KindInvalidInput, Description: "Jumlah perjalanan melebihi batas."},
+
+	{Code: apperror.CodeUnauthorized, Kind: apperror.KindUnauthorized, Description: "Token admin tidak dikirim atau salah, kirim header Authorization: Bearer <ADMIN_TOKEN>."},
+
+	{Code: apperror.CodeNotFound, Kind: apperror.KindNotFound, Description: "Data tidak ditemukan."},
+	{Code: apperror.CodeRouteNotFound, Kind: apperror.KindNotFound, Description: "Endpoint tidak ada."},
+	{Code: apperror.CodeStationNotFound, Kind: apperror.KindNotFound, Description: "ID stasiun tidak ditemukan."},
+	{Code: apperror.CodeLineNotFound, Kind: apperror.KindNotFound, Description: "ID lintasan tidak ditemukan."},
+	{Code: apperror.CodeFareNotFound, Kind: apperror.KindNotFound, Description: "Tarif antara dua stasiun tidak tersedia."},
+	{Code: apperror.CodeErrorCodeNotFound, Kind: apperror.KindNotFound, Description: "Kode error tidak ada di katalog."},
+	{Code: apperror.CodeNoNextTrain, Kind: apperror.KindNoService, Description: "Tidak ada kereta pada rentang waktu yang diminta."},
+
+	{Code: apperror.CodeDataQuality, Kind: apperror.KindUpstreamMalformed, Description: "Data tarif/waktu tempuh dari API MRT tidak bisa diproses."},
+	{Code: apperror.CodeLineUnavailable, Kind: apperror.KindUpstreamMalformed, Description: "Urutan stasiun tidak bisa diturunkan dari data API MRT."},
+	{Code: apperror.CodeUpstreamMalformed, Kind: apperror.KindUpstreamMalformed, Description: "Payload API MRT bukan JSON yang valid."},
+	{Code: apperror.CodeUpstreamSchema, Kind: apperror.KindUpstreamMalformed, Description: "Struktur payload API MRT berubah (field wajib kosong), lihat GET /v1/admin/data-quality."},
+	{Code: apperror.CodeUpstreamBadResponse, Kind: apperror.KindUpstreamMalformed, Description: "API MRT membalas dengan status yang tidak terduga."},
+
+	{Code: apperror.CodeUpstreamUnavailable, Kind: apperror.KindUpstreamUnavailable, Description: "API MRT tidak bisa dihubungi dan belum ada snapshot."},
+	{Code: apperror.CodeUpstreamCircuitOpen, Kind: apperror.KindUpstreamUnavailable, Description: "API MRT sedang dianggap down (circuit breaker terbuka)."},
+	{Code: apperror.CodeSnapshotUnavailable, Kind: apperror.KindUpstreamUnavailable, Description: "Mode offline aktif tapi snapshot belum tersedia."},
+
+	{Code: apperror.CodeUpstreamTimeout, Kind: apperror.KindUpstreamTimeout, Description: "API MRT terlalu lama membalas."},
+	{Code: apperror.CodeRequestTimeout, Kind: apperror.KindUpstreamTimeout, Description: "Batas waktu request habis sebelum data tersedia."},
+
+	{Code: apperror.CodeInternal, Kind: apperror.KindInternal, Description: "Kesalahan tak terduga di server."},
+	{Code: apperror.CodeReloadFailed, Kind: apperror.KindInternal, Description: "File konfigurasi gagal dibaca ulang, konfigurasi lama tetap dipakai."},
+})
+
+func buildCatalogue(entries []CodeInfo) map[string]CodeInfo {
+	catalogue := make(map[string]CodeInfo, len(entries))
+	for _, entry := range entries {
+		entry.Status = statusOf(entry.Kind)
+		entry.DocURL = DocURL(entry.Code)
+		catalogue[entry.Code] = entry
+	}
+
+	return catalogue
+}
+
+// DocURL mengembalikan link dokumentasi untuk kode error.
+func DocURL(code string) string {
+	return DocsBasePath + code
+}
+
+// Codes mengembalikan seluruh katalog, diurutkan berdasarkan status lalu kode.
+func Codes() []CodeInfo {
+	list := make([]CodeInfo, 0, len(Catalogue))
+	for _, info := range Catalogue {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Status != list[j].Status {
+			return list[i].Status < list[j].Status
+		}
+		return list[i].Code < list[j].Code
+	})
+
+	return list
+}
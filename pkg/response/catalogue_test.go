@@ -0,0 +1,91 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCatalogueStatus(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: apperror.CodeValidationFailed, want: http.StatusBadRequest},
+		{code: apperror.CodeInvalidStationID, want: http.StatusBadRequest},
+		{code: apperror.CodeUnauthorized, want: http.StatusUnauthorized},
+		{code: apperror.CodeStationNotFound, want: http.StatusNotFound},
+		{code: apperror.CodeNoNextTrain, want: http.StatusNotFound},
+		{code: apperror.CodeUpstreamSchema, want: http.StatusBadGateway},
+		{code: apperror.CodeUpstreamCircuitOpen, want: http.StatusServiceUnavailable},
+		{code: apperror.CodeRequestTimeout, want: http.StatusGatewayTimeout},
+		{code: apperror.CodeReloadFailed, want: http.StatusInternalServerError},
+		{code: apperror.CodeInternal, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			info, ok := Catalogue[tt.code]
+			if !ok {
+				t.Fatalf("%s missing from Catalogue", tt.code)
+			}
+			if info.Status != tt.want {
+				t.Errorf("Status = %d, want %d", info.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogueEntries(t *testing.T) {
+	codes := Codes()
+	if len(codes) != len(Catalogue) {
+		t.Fatalf("Codes() returned %d entries, Catalogue has %d", len(codes), len(Catalogue))
+	}
+
+	for i, info := range codes {
+		if info.Status != statusOf(info.Kind) {
+			t.Errorf("%s: Status %d does not match kind status %d", info.Code, info.Status, statusOf(info.Kind))
+		}
+		if info.DocURL != DocsBasePath+info.Code || info.Description == "" {
+			t.Errorf("%s: DocURL = %q, Description = %q", info.Code, info.DocURL, info.Description)
+		}
+		if i > 0 {
+			prev := codes[i-1]
+			if prev.Status > info.Status || (prev.Status == info.Status && prev.Code >= info.Code) {
+				t.Errorf("Codes() not sorted at %d: %s (%d) before %s (%d)", i, prev.Code, prev.Status, info.Code, info.Status)
+			}
+		}
+	}
+}
+
+func TestErrorDocURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "catalogued code", code: apperror.CodeStationNotFound, want: DocsBasePath + apperror.CodeStationNotFound},
+		{name: "unknown code", code: "SOMETHING_ELSE", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+
+			Error(ctx, http.StatusNotFound, tt.code, "not found")
+
+			var resp APIError
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.DocURL != tt.want {
+				t.Errorf("doc_url = %q, want %q", resp.DocURL, tt.want)
+			}
+		})
+	}
+}
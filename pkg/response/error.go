@@ -1,29 +1,121 @@
 package response
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
 
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
+// StatusClientClosedRequest dipakai (mengikuti nginx) saat client menutup koneksi sebelum response dikirim.
+const StatusClientClosedRequest = 499
+
+// FieldError adalah detail error untuk satu field input.
+type FieldError = apperror.FieldError
+
+// APIError adalah format response error.
+//   - ErrorCode → kode stabil yang bisa dipakai client untuk membedakan jenis error
+//     tanpa membaca pesan (contoh: "STATION_NOT_FOUND", "UPSTREAM_TIMEOUT"), lihat Catalogue.
+//   - Details   → detail per field untuk error validasi.
+//   - RequestID → ID request (sama dengan header X-Request-ID), untuk dilacak di log.
+//   - DocURL    → link dokumentasi kode error.
 type APIError struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Code      int          `json:"code"`
+	ErrorCode string       `json:"error_code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	DocURL    string       `json:"doc_url,omitempty"`
+	Data      interface{}  `json:"data"`
+}
+
+func Error(ctx *gin.Context, status int, errorCode, message string) {
+	ErrorWithDetails(ctx, status, errorCode, message, nil)
 }
 
-func Error(ctx *gin.Context, status int, message string) {
-	ctx.JSON(status, APIError{
-		Code:    status,
-		Message: message,
-		Data:    nil,
-	})
+// ErrorWithDetails sama dengan Error, ditambah detail per field.
+func ErrorWithDetails(ctx *gin.Context, status int, errorCode, message string, details []FieldError) {
+	resp := APIError{
+		Code:      status,
+		ErrorCode: errorCode,
+		Message:   message,
+		Details:   details,
+		RequestID: ctx.GetString(middleware.RequestIDKey),
+		Data:      nil,
+	}
+	if _, ok := Catalogue[errorCode]; ok {
+		resp.DocURL = DocURL(errorCode)
+	}
+
+	ctx.JSON(status, resp)
 }
 
 func BadRequest(ctx *gin.Context, message string) {
-	Error(ctx, http.StatusBadRequest, message)
+	Error(ctx, http.StatusBadRequest, apperror.CodeInvalidInput, message)
 }
 
 func NotFound(ctx *gin.Context, message string) {
-	Error(ctx, http.StatusNotFound, message)
+	Error(ctx, http.StatusNotFound, apperror.CodeNotFound, message)
+}
+
+// kindStatus memetakan golongan error domain ke status HTTP.
+// Dipakai Fail dan juga Catalogue, jadi status di dokumentasi kode error ikut berubah kalau map ini diubah.
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindInvalidInput:        http.StatusBadRequest,
+	apperror.KindUnauthorized:        http.StatusUnauthorized,
+	apperror.KindNotFound:            http.StatusNotFound,
+	apperror.KindNoService:           http.StatusNotFound,
+	apperror.KindUpstreamMalformed:   http.StatusBadGateway,
+	apperror.KindUpstreamUnavailable: http.StatusServiceUnavailable,
+	apperror.KindUpstreamTimeout:     http.StatusGatewayTimeout,
+	apperror.KindInternal:            http.StatusInternalServerError,
+}
+
+// statusOf mengembalikan status HTTP untuk kind, 500 kalau kind tidak dikenal.
+func statusOf(kind apperror.Kind) int {
+	if status, ok := kindStatus[kind]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Fail mengirim response error sesuai jenis err. Ini satu-satunya tempat
+// error dari usecase diterjemahkan ke status HTTP:
+// - *apperror.Error          → status sesuai Kind, error_code sesuai Code.
+// - context.DeadlineExceeded → 504, batas waktu request habis.
+// - context.Canceled         → 499 tanpa body, client sudah memutus koneksi.
+// - selain itu               → 500.
+//
+// Untuk status 500, pesan error asli hanya ditulis ke log (bersama request ID),
+// client cukup menerima pesan umum supaya detail internal tidak bocor.
+func Fail(ctx *gin.Context, err error) {
+	if appErr, ok := apperror.As(err); ok {
+		status := statusOf(appErr.Kind)
+		if status == http.StatusInternalServerError {
+			internalError(ctx, appErr.Code, err)
+			return
+		}
+		ErrorWithDetails(ctx, status, appErr.Code, err.Error(), appErr.Fields)
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		Error(ctx, http.StatusGatewayTimeout, apperror.CodeRequestTimeout, "request timed out while waiting for upstream data")
+	case errors.Is(err, context.Canceled):
+		ctx.AbortWithStatus(StatusClientClosedRequest)
+	default:
+		internalError(ctx, apperror.CodeInternal, err)
+	}
+}
+
+// internalError mencatat err ke log lalu mengirim 500 dengan pesan umum.
+func internalError(ctx *gin.Context, code string, err error) {
+	log.Printf("request %s: internal error: %v", ctx.GetString(middleware.RequestIDKey), err)
+	Error(ctx, http.StatusInternalServerError, code, "internal server error")
 }
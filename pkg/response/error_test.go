@@ -0,0 +1,92 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/apperror"
+	"github.com/IkrmMrbsy/mrt-schedules/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestFail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "invalid input", err: apperror.InvalidInput(apperror.CodeInvalidLimit, "invalid limit"), wantStatus: http.StatusBadRequest, wantCode: apperror.CodeInvalidLimit, wantMessage: "invalid limit"},
+		{name: "not found", err: apperror.NotFound(apperror.CodeStationNotFound, "station not found"), wantStatus: http.StatusNotFound, wantCode: apperror.CodeStationNotFound},
+		{name: "no service", err: apperror.NoService(apperror.CodeNoNextTrain, "no next train"), wantStatus: http.StatusNotFound, wantCode: apperror.CodeNoNextTrain},
+		{name: "upstream malformed", err: apperror.New(apperror.KindUpstreamMalformed, apperror.CodeUpstreamSchema, "bad payload"), wantStatus: http.StatusBadGateway, wantCode: apperror.CodeUpstreamSchema},
+		{name: "upstream unavailable", err: apperror.New(apperror.KindUpstreamUnavailable, apperror.CodeUpstreamCircuitOpen, "down"), wantStatus: http.StatusServiceUnavailable, wantCode: apperror.CodeUpstreamCircuitOpen},
+		{name: "upstream timeout", err: apperror.New(apperror.KindUpstreamTimeout, apperror.CodeUpstreamTimeout, "slow"), wantStatus: http.StatusGatewayTimeout, wantCode: apperror.CodeUpstreamTimeout},
+		{name: "wrapped apperror", err: fmt.Errorf("leg 1: %w", apperror.NotFound(apperror.CodeFareNotFound, "fare not found")), wantStatus: http.StatusNotFound, wantCode: apperror.CodeFareNotFound},
+		{name: "deadline", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), wantStatus: http.StatusGatewayTimeout, wantCode: apperror.CodeRequestTimeout},
+		{name: "internal kind hides detail", err: apperror.New(apperror.KindInternal, apperror.CodeInternal, "secret path /etc/x"), wantStatus: http.StatusInternalServerError, wantCode: apperror.CodeInternal, wantMessage: "internal server error"},
+		{name: "plain error hides detail", err: errors.New("dial tcp 10.0.0.1:443: refused"), wantStatus: http.StatusInternalServerError, wantCode: apperror.CodeInternal, wantMessage: "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rec)
+			ctx.Set(middleware.RequestIDKey, "req-1")
+
+			Fail(ctx, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var resp APIError
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Code != tt.wantStatus || resp.ErrorCode != tt.wantCode || resp.RequestID != "req-1" {
+				t.Errorf("response = %+v, want code %d, error_code %s, request_id req-1", resp, tt.wantStatus, tt.wantCode)
+			}
+			if tt.wantMessage != "" && resp.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", resp.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestFailCanceled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+
+	Fail(ctx, context.Canceled)
+
+	if ctx.Writer.Status() != StatusClientClosedRequest || rec.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q, want %d without body", ctx.Writer.Status(), rec.Body, StatusClientClosedRequest)
+	}
+}
+
+func TestFailValidationDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+
+	Fail(ctx, apperror.Validation(
+		apperror.FieldError{Field: "id", Code: apperror.CodeInvalidStationID, Message: "id must be a numeric station id"},
+		apperror.FieldError{Field: "limit", Code: apperror.CodeInvalidLimit, Message: "invalid limit"},
+	))
+
+	var resp APIError
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || resp.ErrorCode != apperror.CodeValidationFailed || len(resp.Details) != 2 {
+		t.Errorf("status = %d, response = %+v, want 400 VALIDATION_FAILED with 2 details", rec.Code, resp)
+	}
+}
@@ -2,6 +2,8 @@ package response
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,10 +12,12 @@ import (
 // Semua endpoint API akan memakai format ini supaya konsisten.
 // - Code    → angka status HTTP (contoh: 200, 400, 500).
 // - Message → pesan singkat tentang hasil request.
+// - Stale   → true kalau data berasal dari snapshot lama (misalnya upstream sedang down).
 // - Data    → isi data utama (bisa apa saja: list, object, atau nil).
 type APISuccess struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
+	Stale   bool        `json:"stale"`
 	Data    interface{} `json:"data"`
 }
 
@@ -24,3 +28,22 @@ func Success(ctx *gin.Context, data interface{}) {
 		Data:    data,
 	})
 }
+
+// SuccessWithSnapshot sama seperti Success, tapi ikut menyertakan umur data:
+// - Header X-Snapshot-Age        → umur snapshot dalam detik.
+// - Header X-Snapshot-Fetched-At → waktu snapshot diambil dari upstream (RFC3339).
+// - Field "stale" di envelope    → true kalau snapshot sudah kadaluarsa.
+func SuccessWithSnapshot(ctx *gin.Context, data interface{}, fetchedAt time.Time, stale bool) {
+	if !fetchedAt.IsZero() {
+		age := int(time.Since(fetchedAt).Seconds())
+		ctx.Header("X-Snapshot-Age", strconv.Itoa(age))
+		ctx.Header("X-Snapshot-Fetched-At", fetchedAt.Format(time.RFC3339))
+	}
+
+	ctx.JSON(http.StatusOK, APISuccess{
+		Code:    http.StatusOK,
+		Message: "success",
+		Stale:   stale,
+		Data:    data,
+	})
+}
@@ -0,0 +1,131 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FormatVersion adalah versi format file snapshot.
+// Naikkan angka ini kalau struktur Record berubah supaya file lama tidak salah dibaca.
+const FormatVersion = 1
+
+// ErrNotFound dikembalikan kalau snapshot untuk key tertentu belum pernah disimpan.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Record adalah isi satu file snapshot.
+// - Version   → versi format file (lihat FormatVersion).
+// - Key       → nama resource (contoh: "stations").
+// - FetchedAt → waktu payload diambil dari upstream.
+// - Checksum  → sha256 dari payload, untuk mendeteksi file rusak.
+// - Payload   → body JSON asli dari upstream.
+type Record struct {
+	Version   int             `json:"version"`
+	Key       string          `json:"key"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Checksum  string          `json:"checksum"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// Store menyimpan snapshot payload upstream ke disk, satu file per key.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore membuat Store baru dan memastikan direktori tujuan sudah ada.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Save menulis payload ke file snapshot milik key.
+// Penulisan dilakukan ke file sementara lalu di-rename, jadi file lama
+// tidak pernah setengah tertulis kalau proses mati di tengah jalan.
+func (s *Store) Save(key string, payload []byte, fetchedAt time.Time) error {
+	// Payload di-compact dulu, karena encoder JSON juga akan meng-compact RawMessage
+	// dan checksum harus dihitung dari isi yang benar-benar tersimpan.
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, payload); err != nil {
+		return err
+	}
+
+	// Escape HTML dimatikan supaya payload tersimpan persis seperti aslinya
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(Record{
+		Version:   FormatVersion,
+		Key:       key,
+		FetchedAt: fetchedAt,
+		Checksum:  checksum(compacted.Bytes()),
+		Payload:   compacted.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Load membaca snapshot terakhir untuk key dan memverifikasi versi serta checksum-nya.
+func (s *Store) Load(key string) (*Record, error) {
+	s.mu.Lock()
+	data, err := os.ReadFile(s.path(key))
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("snapshot %s is corrupted: %w", key, err)
+	}
+	if record.Version != FormatVersion {
+		return nil, fmt.Errorf("snapshot %s has unsupported version %d", key, record.Version)
+	}
+	if record.Checksum != checksum(record.Payload) {
+		return nil, fmt.Errorf("snapshot %s checksum mismatch", key)
+	}
+
+	return &record, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+func checksum(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,157 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := NewStore(filepath.Join(t.TempDir(), "snapshots"))
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	return store
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := newStore(t)
+	fetchedAt := time.Date(2026, 10, 18, 7, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	if err := store.Save("stations", []byte("[\n  {\"nid\": \"38\", \"title\": \"Lebak Bulus <Grab>\"}\n]"), fetchedAt); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	record, err := store.Load("stations")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if record.Key != "stations" || record.Version != FormatVersion || !record.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("Load() = key %q, version %d, fetched_at %v; want stations, %d, %v", record.Key, record.Version, record.FetchedAt, FormatVersion, fetchedAt)
+	}
+	// Payload tersimpan dalam bentuk compact, tanpa escape HTML
+	if want := `[{"nid":"38","title":"Lebak Bulus <Grab>"}]`; string(record.Payload) != want {
+		t.Errorf("Payload = %s, want %s", record.Payload, want)
+	}
+}
+
+func TestStoreLoadRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(t *testing.T, path string) // nil = snapshot tidak pernah disimpan
+		wantErr  error
+		wantText string
+	}{
+		{name: "not saved", wantErr: ErrNotFound},
+		{
+			name:     "checksum mismatch",
+			edit:     editRecord(func(r *Record) { r.Payload = json.RawMessage(`[{"nid":"99"}]`) }),
+			wantText: "checksum mismatch",
+		},
+		{
+			name:     "unknown version",
+			edit:     editRecord(func(r *Record) { r.Version = FormatVersion + 1 }),
+			wantText: "unsupported version",
+		},
+		{
+			name: "corrupted file",
+			edit: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, []byte(`{"version":1,"payload":`), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantText: "corrupted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore(t)
+			if tt.edit != nil {
+				if err := store.Save("stations", []byte(`[{"nid":"38"}]`), time.Now()); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+				tt.edit(t, store.path("stations"))
+			}
+
+			record, err := store.Load("stations")
+			if err == nil {
+				t.Fatalf("Load() = %+v, want error", record)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+// editRecord mengubah isi file snapshot lewat fn tanpa menghitung ulang checksum-nya.
+func editRecord(fn func(r *Record)) func(t *testing.T, path string) {
+	return func(t *testing.T, path string) {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			t.Fatal(err)
+		}
+		fn(&record)
+
+		data, err = json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStoreSaveReplaces(t *testing.T) {
+	store := newStore(t)
+	first := time.Now().Add(-time.Hour).Truncate(time.Second)
+	second := first.Add(30 * time.Minute)
+
+	if err := store.Save("stations", []byte(`[{"nid":"1"}]`), first); err != nil {
+		t.Fatalf("first Save() error = %v", err)
+	}
+	if err := store.Save("stations", []byte(`[{"nid":"2"}]`), second); err != nil {
+		t.Fatalf("second Save() error = %v", err)
+	}
+	// Payload yang bukan JSON ditolak sebelum menyentuh file yang sudah ada
+	if err := store.Save("stations", []byte(`<html>maintenance</html>`), second.Add(time.Minute)); err == nil {
+		t.Fatal("Save() of invalid payload error = nil, want error")
+	}
+
+	record, err := store.Load("stations")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if string(record.Payload) != `[{"nid":"2"}]` || !record.FetchedAt.Equal(second) {
+		t.Errorf("Load() = %s at %v, want second snapshot at %v", record.Payload, record.FetchedAt, second)
+	}
+
+	// Tidak ada file sementara yang tertinggal setelah rename
+	entries, err := os.ReadDir(store.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "stations.json" {
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("snapshot dir = %v, want only stations.json", names)
+	}
+}